@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/baseline"
+)
+
+var baselineCommand = &cli.Command{
+	Name:  "baseline",
+	Usage: "manage baselines of accepted failures",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "generate",
+			Usage:     "generate a baseline accepting each failed module in the JSON results of a scan",
+			ArgsUsage: "path/to/results.json",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "output",
+					Aliases: []string{"o"},
+					Usage:   "write the baseline to the supplied file, rather than stdout",
+				},
+			},
+			Action: generateBaseline,
+		},
+	},
+}
+
+func generateBaseline(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("path to a single JSON results file must be supplied")
+	}
+	summary, err := readJSON(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if path := c.String("output"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create file for baseline: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return baseline.Write(w, baseline.Generate(summary))
+}
+
+// explainEntry describes the failure accepted by a baseline entry
+func explainEntry(e baseline.Entry) string {
+	decision, _ := e.Decision.MarshalText()
+	if len(e.Licenses) == 0 {
+		return string(decision)
+	}
+	return fmt.Sprintf("%s: %s", decision, strings.Join(e.Licenses, ", "))
+}
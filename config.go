@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/scan"
+	"gopkg.in/yaml.v2"
+)
+
+var configCommand = &cli.Command{
+	Name:  "config",
+	Usage: "inspect lichen config files",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "validate",
+			Usage:     "validate a config, after resolving any configs it extends",
+			ArgsUsage: "path/to/lichen.yaml",
+			Action:    validateConfig,
+		},
+		{
+			Name:      "print",
+			Usage:     "print the effective config, after resolving any configs it extends",
+			ArgsUsage: "path/to/lichen.yaml",
+			Action:    printConfig,
+		},
+	},
+}
+
+func printConfig(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("path to a single config file must be supplied")
+	}
+	conf, err := parseConfig(c.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	b, err := yaml.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+func validateConfig(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("path to a single config file must be supplied")
+	}
+	if _, err := parseConfig(c.Args().First()); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	fmt.Println("config is valid")
+	return nil
+}
+
+// parseConfig reads the config at the supplied path, merging it on top of any configs it extends, and validates the
+// result
+func parseConfig(path string) (scan.Config, error) {
+	if path == "" {
+		return scan.Config{}, nil
+	}
+	conf, err := loadConfig(path, nil, make(map[string]bool))
+	if err != nil {
+		return scan.Config{}, err
+	}
+	if err := conf.Validate(); err != nil {
+		return scan.Config{}, err
+	}
+	return conf, nil
+}
+
+// loadConfig recursively loads the config at the supplied path. Configs listed in `extends` are resolved relative to
+// the directory of the config that lists them, and are merged in order before the config itself is merged on top.
+// Configs extended more than once (e.g. a base shared by two of the configs extended) are only merged the first time,
+// as recorded by loaded.
+func loadConfig(path string, visiting []string, loaded map[string]bool) (scan.Config, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return scan.Config{}, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for _, v := range visiting {
+		if v == abs {
+			return scan.Config{}, fmt.Errorf("config %q extends itself (via %v)", path, visiting)
+		}
+	}
+	visiting = append(visiting, abs)
+
+	b, err := ioutil.ReadFile(abs)
+	if err != nil {
+		return scan.Config{}, fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	// unknown and duplicate keys are rejected, to avoid typos silently disabling parts of the config
+	var conf scan.Config
+	if err := yaml.UnmarshalStrict(b, &conf); err != nil {
+		return scan.Config{}, fmt.Errorf("failed to parse yaml in %q: %w", path, err)
+	}
+
+	var merged scan.Config
+	for _, base := range conf.Extends {
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(filepath.Dir(abs), base)
+		}
+		if loaded[filepath.Clean(base)] {
+			continue
+		}
+		baseConf, err := loadConfig(base, visiting, loaded)
+		if err != nil {
+			return scan.Config{}, err
+		}
+		merged = merged.Merge(baseConf)
+	}
+	loaded[abs] = true
+	return merged.Merge(conf), nil
+}
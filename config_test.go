@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		files     map[string]string // configs to write, keyed by path, with the config loaded being a.yaml
+		overrides []string          // paths of the overrides of the loaded config, in the order they are merged
+		err       string
+	}{
+		{
+			name: "chain",
+			files: map[string]string{
+				"a.yaml":      "extends: [base/b.yaml]\noverride: [{path: github.com/a/a, licenses: [MIT]}]",
+				"base/b.yaml": "extends: [c.yaml]\noverride: [{path: github.com/b/b, licenses: [MIT]}]",
+				"base/c.yaml": "override: [{path: github.com/c/c, licenses: [MIT]}]",
+			},
+			overrides: []string{"github.com/c/c", "github.com/b/b", "github.com/a/a"},
+		},
+		{
+			name: "diamond",
+			files: map[string]string{
+				"a.yaml": "extends: [b.yaml, c.yaml]\noverride: [{path: github.com/a/a, licenses: [MIT]}]",
+				"b.yaml": "extends: [d.yaml]\noverride: [{path: github.com/b/b, licenses: [MIT]}]",
+				"c.yaml": "extends: [./d.yaml]\noverride: [{path: github.com/c/c, licenses: [MIT]}]",
+				"d.yaml": "override: [{path: github.com/d/d, licenses: [MIT]}]",
+			},
+			overrides: []string{"github.com/d/d", "github.com/b/b", "github.com/c/c", "github.com/a/a"},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.yaml": "extends: [b.yaml]",
+				"b.yaml": "extends: [a.yaml]",
+			},
+			err: "extends itself",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range tc.files {
+				path = filepath.Join(dir, filepath.FromSlash(path))
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			}
+
+			conf, err := loadConfig(filepath.Join(dir, "a.yaml"), nil, make(map[string]bool))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			var overrides []string
+			for _, o := range conf.Overrides {
+				overrides = append(overrides, o.Path)
+			}
+			assert.Equal(t, tc.overrides, overrides)
+		})
+	}
+}
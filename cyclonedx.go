@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/cyclonedx"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// writeCycloneDX writes CycloneDX BOMs for the scan results in the supplied format (json or xml). If perBinary is set,
+// path is a directory to which a BOM for each binary is written, otherwise a single BOM including every binary is
+// written to path.
+func writeCycloneDX(path, format string, perBinary bool, summary scan.Summary) error {
+	var (
+		write func(io.Writer, cyclonedx.BOM) error
+		ext   string
+	)
+	switch format {
+	case "json":
+		write, ext = cyclonedx.WriteJSON, ".cdx.json"
+	case "xml":
+		write, ext = cyclonedx.WriteXML, ".cdx.xml"
+	default:
+		return fmt.Errorf("unrecognised CycloneDX format %q (expected one of: json, xml)", format)
+	}
+
+	created := time.Now()
+	if !perBinary {
+		bom, err := cyclonedx.Combined(summary, created)
+		if err != nil {
+			return err
+		}
+		return writeFile(path, func(w io.Writer) error { return write(w, bom) })
+	}
+
+	paths, err := binaryFiles(path, ext, summary.Binaries)
+	if err != nil {
+		return err
+	}
+	boms, err := cyclonedx.PerBinary(summary, created)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for CycloneDX BOMs: %w", err)
+	}
+	for i, bom := range boms {
+		bom := bom
+		if err := writeFile(paths[i], func(w io.Writer) error { return write(w, bom) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
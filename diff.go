@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/diff"
+)
+
+var diffCommand = &cli.Command{
+	Name:      "diff",
+	Usage:     "report the modules added, removed and changed between the JSON results of two scans",
+	ArgsUsage: "path/to/old.json path/to/new.json",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "output format (text, json or markdown)",
+			Value:   "text",
+		},
+	},
+	Action: diffResults,
+}
+
+func diffResults(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return errors.New("paths to two JSON results files must be supplied")
+	}
+	var write func(io.Writer, diff.Diff) error
+	switch format := c.String("format"); format {
+	case "text":
+		write = diff.WriteText
+	case "json":
+		write = diff.WriteJSON
+	case "markdown":
+		write = diff.WriteMarkdown
+	default:
+		return fmt.Errorf("unrecognised format %q (expected one of: text, json, markdown)", format)
+	}
+
+	old, err := readJSON(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	new, err := readJSON(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	return write(os.Stdout, diff.Compare(old, new))
+}
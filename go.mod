@@ -8,6 +8,7 @@ require (
 	github.com/muesli/termenv v0.11.0
 	github.com/stretchr/testify v1.7.1
 	github.com/urfave/cli/v2 v2.4.0
+	golang.org/x/mod v0.12.0
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -0,0 +1,157 @@
+package baseline
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/uw-labs/lichen/internal/scan"
+	"gopkg.in/yaml.v2"
+)
+
+// Baseline records the failures accepted at the time it was generated, so that only newly introduced problems cause
+// lichen to fail
+type Baseline struct {
+	Modules []Entry `yaml:"modules"`
+}
+
+// Entry accepts a failed decision for a module. Entries are keyed on module path rather than version, so that
+// upgrading a module does not invalidate its entry unless the decision or non-permitted licenses change.
+type Entry struct {
+	Path     string        `yaml:"path"`
+	Decision scan.Decision `yaml:"decision"`
+	Licenses []string      `yaml:"licenses,omitempty"` // non-permitted licenses accepted for the module
+}
+
+// covers returns true if the entry accepts the failure of the supplied module
+func (e Entry) covers(m scan.EvaluatedModule) bool {
+	if e.Path != m.Path || !sameDecision(e.Decision, m.Decision) {
+		return false
+	}
+	for _, lic := range m.NotPermitted {
+		if !contains(e.Licenses, lic) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDecision returns true if the decisions match. Baselines generated by earlier versions record each unresolvable
+// decision as unresolvable-license, which is treated as matching each of the more specific unresolvable decisions.
+func sameDecision(accepted, d scan.Decision) bool {
+	if accepted == scan.DecisionNotAllowedUnresolvableLicense {
+		return d.Unresolvable()
+	}
+	return accepted == d
+}
+
+// Generate returns a baseline accepting each failed module in the summary, including those already accepted by a
+// previous baseline
+func Generate(summary scan.Summary) Baseline {
+	var b Baseline
+	for _, m := range summary.Modules {
+		if m.Severity != scan.SeverityError {
+			continue
+		}
+		e := Entry{
+			Path:     m.Path,
+			Decision: m.Decision,
+			Licenses: append([]string(nil), m.NotPermitted...),
+		}
+		sort.Strings(e.Licenses)
+		if !b.has(e) {
+			b.Modules = append(b.Modules, e)
+		}
+	}
+	sort.SliceStable(b.Modules, func(i, j int) bool {
+		return b.Modules[i].Path < b.Modules[j].Path
+	})
+	return b
+}
+
+// has returns true if an identical entry has already been recorded (the same module path can be used at several
+// versions)
+func (b Baseline) has(e Entry) bool {
+	for _, existing := range b.Modules {
+		if existing.Path == e.Path && existing.Decision == e.Decision && equal(existing.Licenses, e.Licenses) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply marks each failed module accepted by the baseline as baselined, returning the entries that accepted none of
+// the failed modules and are therefore no longer needed
+func (b Baseline) Apply(summary *scan.Summary) (unneeded []Entry) {
+	used := make([]bool, len(b.Modules))
+	for i, m := range summary.Modules {
+		if m.Severity != scan.SeverityError {
+			continue
+		}
+		for j, e := range b.Modules {
+			if e.covers(m) {
+				summary.Modules[i].Baselined = true
+				used[j] = true
+			}
+		}
+	}
+	for i, e := range b.Modules {
+		if !used[i] {
+			unneeded = append(unneeded, e)
+		}
+	}
+	return unneeded
+}
+
+// Read reads the baseline at the supplied path
+func Read(path string) (Baseline, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Baseline{}, fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	var b Baseline
+	if err := yaml.UnmarshalStrict(raw, &b); err != nil {
+		return Baseline{}, fmt.Errorf("failed to parse yaml in %q: %w", path, err)
+	}
+	for i, e := range b.Modules {
+		if e.Path == "" {
+			return Baseline{}, fmt.Errorf("modules[%d]: path must be specified", i)
+		}
+		if e.Decision == 0 {
+			return Baseline{}, fmt.Errorf("modules[%d]: decision must be specified for %s", i, e.Path)
+		}
+	}
+	return b, nil
+}
+
+// Write writes the baseline as YAML
+func Write(w io.Writer, b Baseline) error {
+	raw, err := yaml.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	_, err = w.Write(raw)
+	return err
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
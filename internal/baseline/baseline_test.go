@@ -0,0 +1,70 @@
+package baseline_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/uw-labs/lichen/internal/baseline"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+func evaluated(path, version string, decision scan.Decision, severity scan.Severity, notPermitted ...string) scan.EvaluatedModule {
+	return scan.EvaluatedModule{
+		Module:       model.Module{ModuleReference: model.ModuleReference{Path: path, Version: version}},
+		Decision:     decision,
+		NotPermitted: notPermitted,
+		Severity:     severity,
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	summary := scan.Summary{Modules: []scan.EvaluatedModule{
+		evaluated("github.com/foo/bar", "v1.0.0", scan.DecisionNotAllowedLicenseNotPermitted, scan.SeverityError, "GPL-3.0", "AGPL-3.0"),
+		evaluated("github.com/baz/qux", "v1.0.0", scan.DecisionNotAllowedNoLicenseFiles, scan.SeverityError),
+		evaluated("github.com/baz/qux", "v1.1.0", scan.DecisionNotAllowedNoLicenseFiles, scan.SeverityError),
+		evaluated("github.com/warn/only", "v1.0.0", scan.DecisionNotAllowedLicenseNotPermitted, scan.SeverityWarn, "LGPL-3.0"),
+		evaluated("github.com/ok/ok", "v1.0.0", scan.DecisionAllowed, 0),
+	}}
+
+	expected := baseline.Baseline{Modules: []baseline.Entry{
+		{Path: "github.com/baz/qux", Decision: scan.DecisionNotAllowedNoLicenseFiles},
+		{Path: "github.com/foo/bar", Decision: scan.DecisionNotAllowedLicenseNotPermitted, Licenses: []string{"AGPL-3.0", "GPL-3.0"}},
+	}}
+	assert.Equal(t, expected, baseline.Generate(summary))
+}
+
+func TestBaseline_Apply(t *testing.T) {
+	b := baseline.Baseline{Modules: []baseline.Entry{
+		{Path: "github.com/foo/bar", Decision: scan.DecisionNotAllowedLicenseNotPermitted, Licenses: []string{"GPL-3.0"}},
+		{Path: "github.com/baz/qux", Decision: scan.DecisionNotAllowedUnresolvableLicense},
+		{Path: "github.com/gone/away", Decision: scan.DecisionNotAllowedUnresolvableLicense},
+		{Path: "github.com/legacy/entry", Decision: scan.DecisionNotAllowedUnresolvableLicense},
+	}}
+	summary := scan.Summary{Modules: []scan.EvaluatedModule{
+		// accepted, despite being upgraded
+		evaluated("github.com/foo/bar", "v1.2.0", scan.DecisionNotAllowedLicenseNotPermitted, scan.SeverityError, "GPL-3.0"),
+		// newly introduced license
+		evaluated("github.com/foo/bar", "v2.0.0", scan.DecisionNotAllowedLicenseNotPermitted, scan.SeverityError, "GPL-3.0", "AGPL-3.0"),
+		// decision changed
+		evaluated("github.com/baz/qux", "v1.0.0", scan.DecisionNotAllowedLicenseNotPermitted, scan.SeverityError, "GPL-3.0"),
+		// accepted by an entry from a baseline predating the more specific unresolvable decisions
+		evaluated("github.com/legacy/entry", "v1.0.0", scan.DecisionNotAllowedNoLicenseFiles, scan.SeverityError),
+	}}
+
+	unneeded := b.Apply(&summary)
+
+	assert.Equal(t, []bool{true, false, false, true}, []bool{
+		summary.Modules[0].Baselined,
+		summary.Modules[1].Baselined,
+		summary.Modules[2].Baselined,
+		summary.Modules[3].Baselined,
+	})
+	assert.False(t, summary.Modules[0].Failed())
+	assert.True(t, summary.Modules[1].Failed())
+	assert.Equal(t, []baseline.Entry{
+		{Path: "github.com/baz/qux", Decision: scan.DecisionNotAllowedUnresolvableLicense},
+		{Path: "github.com/gone/away", Decision: scan.DecisionNotAllowedUnresolvableLicense},
+	}, unneeded)
+}
@@ -59,12 +59,14 @@ func Parse(info string) ([]model.BuildInfo, error) {
 				return nil, fmt.Errorf("invalid mod line: %s", l)
 			}
 			current.ModulePath = parts[2]
+			current.ModuleVersion = parts[3]
 		case "dep", "=>":
 			switch len(parts) {
 			case 5:
 				if parts[3] == "(devel)" {
 					// "mod" line in disguise (Go 1.18)
 					current.ModulePath = parts[2]
+					current.ModuleVersion = parts[3]
 				} else {
 					current.ModuleRefs = append(current.ModuleRefs, model.ModuleReference{
 						Path:    parts[2],
@@ -26,9 +26,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        "/tmp/lichen",
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          "/tmp/lichen",
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/cpuguy83/go-md2man/v2",
@@ -48,9 +49,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        "/tmp/lichen",
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          "/tmp/lichen",
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/uw-labs/go-md2man/v2",
@@ -73,9 +75,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        "/tmp/lichen",
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          "/tmp/lichen",
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/cpuguy83/go-md2man/v2",
@@ -84,9 +87,10 @@ func TestParse(t *testing.T) {
 					},
 				},
 				{
-					Path:        "/tmp/lichen2",
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          "/tmp/lichen2",
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/google/goterm",
@@ -105,9 +109,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        `C:\lichen.exe`,
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          `C:\lichen.exe`,
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/cpuguy83/go-md2man/v2",
@@ -165,9 +170,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        `/tmp/lichen`,
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          `/tmp/lichen`,
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/cpuguy83/go-md2man/v2",
@@ -189,9 +195,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        `/tmp/lichen`,
-					PackagePath: "github.com/uw-labs/lichen",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          `/tmp/lichen`,
+					PackagePath:   "github.com/uw-labs/lichen",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/johanbrandhorst/protoc-gen-star",
@@ -215,9 +222,10 @@ func TestParse(t *testing.T) {
 `,
 			expected: []model.BuildInfo{
 				{
-					Path:        `lichen`,
-					PackagePath: "command-line-arguments",
-					ModulePath:  "github.com/uw-labs/lichen",
+					Path:          `lichen`,
+					PackagePath:   "command-line-arguments",
+					ModulePath:    "github.com/uw-labs/lichen",
+					ModuleVersion: "(devel)",
 					ModuleRefs: []model.ModuleReference{
 						{
 							Path:    "github.com/cpuguy83/go-md2man/v2",
@@ -231,6 +239,28 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "versioned main module",
+			input: `/tmp/golangci-lint: go1.20.4
+	path	github.com/golangci/golangci-lint/cmd/golangci-lint
+	mod	github.com/golangci/golangci-lint	v1.53.3	h1:CUcRafczT4t1F+mvdkUm6KuOpxUZTl0yWN/rSU6sSMo=
+	dep	golang.org/x/sys	v0.9.0	h1:KS/R3tvhPqvJvwcKfnBHJwwthS11LRhmM5D59eEXa0s=
+`,
+			expected: []model.BuildInfo{
+				{
+					Path:          `/tmp/golangci-lint`,
+					PackagePath:   "github.com/golangci/golangci-lint/cmd/golangci-lint",
+					ModulePath:    "github.com/golangci/golangci-lint",
+					ModuleVersion: "v1.53.3",
+					ModuleRefs: []model.ModuleReference{
+						{
+							Path:    "golang.org/x/sys",
+							Version: "v0.9.0",
+						},
+					},
+				},
+			},
+		},
 		{
 			name:        "unrecognised line",
 			input:       `/tmp/lichen: invalid`,
@@ -0,0 +1,300 @@
+package cyclonedx
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/license"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+const (
+	bomFormat   = "CycloneDX"
+	specVersion = "1.5"
+	xmlns       = "http://cyclonedx.org/schema/bom/1.5"
+
+	// licenseRefPrefix prefixes custom SPDX license identifiers, which may be used in license expressions
+	licenseRefPrefix = "LicenseRef-"
+)
+
+// BOM is a CycloneDX 1.5 bill of materials, with the subset of fields populated by lichen
+type BOM struct {
+	BOMFormat    string       `json:"bomFormat"`
+	SpecVersion  string       `json:"specVersion"`
+	SerialNumber string       `json:"serialNumber"`
+	Version      int          `json:"version"`
+	Metadata     Metadata     `json:"metadata"`
+	Components   []Component  `json:"components,omitempty"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+type Metadata struct {
+	Timestamp string     `json:"timestamp"`
+	Tools     Tools      `json:"tools"`
+	Component *Component `json:"component,omitempty"` // the binary described by the BOM, when there is a single binary
+}
+
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+type Component struct {
+	Type       string          `json:"type"`
+	BOMRef     string          `json:"bom-ref,omitempty"`
+	Name       string          `json:"name"`
+	Version    string          `json:"version,omitempty"`
+	Hashes     []Hash          `json:"hashes,omitempty"`
+	Licenses   []LicenseChoice `json:"licenses,omitempty"`
+	PURL       string          `json:"purl,omitempty"`
+	Properties []Property      `json:"properties,omitempty"`
+	Evidence   *Evidence       `json:"evidence,omitempty"`
+}
+
+type Hash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+// LicenseChoice is either a single license or an SPDX license expression
+type LicenseChoice struct {
+	License    *License `json:"license,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+}
+
+type License struct {
+	ID         string     `json:"id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Evidence records how the licenses of a component were detected
+type Evidence struct {
+	Licenses    []LicenseChoice `json:"licenses,omitempty"`
+	Occurrences []Occurrence    `json:"occurrences,omitempty"`
+}
+
+type Occurrence struct {
+	Location string `json:"location"`
+}
+
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// Combined returns a single BOM including every scanned binary, along with the modules they depend on
+func Combined(summary scan.Summary, created time.Time) (BOM, error) {
+	return newBOM(summary.Binaries, summary.Modules, created)
+}
+
+// PerBinary returns a BOM for each scanned binary, with the binary as the metadata component, along with the modules
+// it depends on
+func PerBinary(summary scan.Summary, created time.Time) ([]BOM, error) {
+	boms := make([]BOM, 0, len(summary.Binaries))
+	for _, bin := range summary.Binaries {
+		var modules []scan.EvaluatedModule
+		for _, m := range summary.Modules {
+			if m.IsUsedBy(bin.Path) {
+				modules = append(modules, m)
+			}
+		}
+		bom, err := newBOM([]model.BuildInfo{bin}, modules, created)
+		if err != nil {
+			return nil, err
+		}
+		boms = append(boms, bom)
+	}
+	return boms, nil
+}
+
+func newBOM(binaries []model.BuildInfo, modules []scan.EvaluatedModule, created time.Time) (BOM, error) {
+	serial, err := serialNumber()
+	if err != nil {
+		return BOM{}, err
+	}
+	bom := BOM{
+		BOMFormat:    bomFormat,
+		SpecVersion:  specVersion,
+		SerialNumber: serial,
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools: Tools{
+				Components: []Component{{Type: "application", Name: "lichen"}},
+			},
+		},
+	}
+
+	deps := make(map[string][]string, len(binaries))
+	for _, bin := range binaries {
+		c := binaryComponent(bin)
+		if len(binaries) == 1 {
+			bom.Metadata.Component = &c
+		} else {
+			bom.Components = append(bom.Components, c)
+		}
+	}
+	for _, m := range modules {
+		c := moduleComponent(m)
+		bom.Components = append(bom.Components, c)
+		for _, bin := range m.UsedBy {
+			deps[bin] = append(deps[bin], c.BOMRef)
+		}
+	}
+	for _, bin := range binaries {
+		bom.Dependencies = append(bom.Dependencies, Dependency{
+			Ref:       binaryRef(bin),
+			DependsOn: deps[bin.Path],
+		})
+	}
+	return bom, nil
+}
+
+func binaryComponent(bin model.BuildInfo) Component {
+	c := Component{
+		Type:    "application",
+		BOMRef:  binaryRef(bin),
+		Name:    filepath.Base(bin.Path),
+		Version: bin.ModuleVersion,
+	}
+	if bin.PackagePath != "" {
+		c.Properties = append(c.Properties, Property{Name: "lichen:package", Value: bin.PackagePath})
+	}
+	return c
+}
+
+// binaryRef returns a reference to the binary, unique within the BOM
+func binaryRef(bin model.BuildInfo) string {
+	return "binary:" + bin.Path
+}
+
+func moduleComponent(m scan.EvaluatedModule) Component {
+	c := Component{
+		Type:     "library",
+		BOMRef:   m.PackageURL(),
+		Name:     m.Path,
+		Version:  m.Version,
+		Licenses: licenseChoices(m.Licenses),
+		PURL:     m.PackageURL(),
+	}
+	if c.BOMRef == "" {
+		c.BOMRef = "module:" + m.ModuleReference.String()
+	}
+	if m.ZipSHA256 != "" {
+		c.Hashes = []Hash{{Algorithm: "SHA-256", Content: m.ZipSHA256}}
+	}
+
+	decision, _ := m.Decision.MarshalText()
+	c.Properties = append(c.Properties, Property{Name: "lichen:decision", Value: string(decision)})
+	if len(m.NotPermitted) > 0 {
+		c.Properties = append(c.Properties, Property{Name: "lichen:notPermitted", Value: strings.Join(m.NotPermitted, ", ")})
+	}
+	if m.Severity != 0 {
+		c.Properties = append(c.Properties, Property{Name: "lichen:severity", Value: m.Severity.String()})
+	}
+	for _, rule := range m.Rules {
+		c.Properties = append(c.Properties, Property{Name: "lichen:rule", Value: fmt.Sprintf("%s %s", rule.Type, rule.Path)})
+	}
+	if m.Main {
+		c.Properties = append(c.Properties, Property{Name: "lichen:main", Value: "true"})
+	}
+
+	// evidence only covers licenses detected in license files, excluding those set by overrides
+	var evidence Evidence
+	for _, lic := range m.Licenses {
+		if lic.Path == "" {
+			continue
+		}
+		l := newLicense(lic.Name)
+		l.Properties = []Property{
+			{Name: "lichen:confidence", Value: strconv.FormatFloat(lic.Confidence, 'f', 2, 64)},
+			{Name: "lichen:path", Value: lic.Path},
+		}
+		evidence.Licenses = append(evidence.Licenses, LicenseChoice{License: &l})
+		if !hasOccurrence(evidence.Occurrences, lic.Path) {
+			evidence.Occurrences = append(evidence.Occurrences, Occurrence{Location: lic.Path})
+		}
+	}
+	if len(evidence.Licenses) > 0 {
+		c.Evidence = &evidence
+	}
+	return c
+}
+
+// licenseChoices returns a single license, or an expression requiring each of the licenses if there are several. An
+// expression can only reference SPDX license identifiers, so if any of the licenses isn't on the SPDX license list,
+// each license is listed individually instead.
+func licenseChoices(licenses []model.License) []LicenseChoice {
+	var (
+		names      []string
+		expressive = true
+	)
+	for _, lic := range licenses {
+		if !contains(names, lic.Name) {
+			names = append(names, lic.Name)
+			expressive = expressive && (license.IsSPDX(lic.Name) || strings.HasPrefix(lic.Name, licenseRefPrefix))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	if len(names) > 1 && expressive {
+		return []LicenseChoice{{Expression: strings.Join(names, " AND ")}}
+	}
+	choices := make([]LicenseChoice, 0, len(names))
+	for _, name := range names {
+		l := newLicense(name)
+		choices = append(choices, LicenseChoice{License: &l})
+	}
+	return choices
+}
+
+// newLicense returns a license identified by its SPDX license identifier, or by name if it isn't on the SPDX license
+// list (including custom "LicenseRef-" identifiers)
+func newLicense(name string) License {
+	if license.IsSPDX(name) {
+		return License{ID: name}
+	}
+	return License{Name: name}
+}
+
+// serialNumber returns a random UUID URN, identifying the BOM
+func serialNumber() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func hasOccurrence(occurrences []Occurrence, location string) bool {
+	for _, o := range occurrences {
+		if o.Location == location {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
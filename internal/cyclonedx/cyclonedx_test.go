@@ -0,0 +1,309 @@
+package cyclonedx_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/cyclonedx"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+var (
+	foo = model.BuildInfo{Path: "/bin/foo", PackagePath: "github.com/acme/foo/cmd/foo", ModulePath: "github.com/acme/foo", ModuleVersion: "v1.0.0"}
+	bar = model.BuildInfo{Path: "/bin/bar", PackagePath: "github.com/acme/bar", ModulePath: "github.com/acme/bar", ModuleVersion: "(devel)"}
+)
+
+func TestCombined(t *testing.T) {
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		expected cyclonedx.Component
+	}{
+		{
+			name: "detected licenses",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.2.0+incompatible"},
+					ZipSHA256:       "53eb3dd144d2620a6d64cc10876691af72ee6b32c921af8f83729cd729526156",
+					Licenses: []model.License{
+						{Name: "MIT", Path: "/mod/LICENSE", Confidence: 0.98},
+						{Name: "LicenseRef-Custom", Path: "/mod/LICENSE", Confidence: 0.9},
+					},
+				},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: cyclonedx.Component{
+				Type:       "library",
+				BOMRef:     "pkg:golang/github.com/abc/xyz@v1.2.0%2Bincompatible",
+				Name:       "github.com/abc/xyz",
+				Version:    "v1.2.0+incompatible",
+				Hashes:     []cyclonedx.Hash{{Algorithm: "SHA-256", Content: "53eb3dd144d2620a6d64cc10876691af72ee6b32c921af8f83729cd729526156"}},
+				Licenses:   []cyclonedx.LicenseChoice{{Expression: "LicenseRef-Custom AND MIT"}},
+				PURL:       "pkg:golang/github.com/abc/xyz@v1.2.0%2Bincompatible",
+				Properties: []cyclonedx.Property{{Name: "lichen:decision", Value: "allowed"}},
+				Evidence: &cyclonedx.Evidence{
+					Licenses: []cyclonedx.LicenseChoice{
+						{License: &cyclonedx.License{ID: "MIT", Properties: []cyclonedx.Property{
+							{Name: "lichen:confidence", Value: "0.98"},
+							{Name: "lichen:path", Value: "/mod/LICENSE"},
+						}}},
+						{License: &cyclonedx.License{Name: "LicenseRef-Custom", Properties: []cyclonedx.Property{
+							{Name: "lichen:confidence", Value: "0.90"},
+							{Name: "lichen:path", Value: "/mod/LICENSE"},
+						}}},
+					},
+					Occurrences: []cyclonedx.Occurrence{{Location: "/mod/LICENSE"}},
+				},
+			},
+		},
+		{
+			name: "licenses set by override aren't evidenced",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/overridden/mod", Version: "v0.1.0"},
+					Licenses:        []model.License{{Name: "BSD-3-Clause", Confidence: 1}},
+				},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"BSD-3-Clause"},
+				Severity:     scan.SeverityError,
+				Rules:        []scan.Rule{{Type: scan.RuleTypeOverride, Path: "github.com/overridden/mod"}},
+			},
+			expected: cyclonedx.Component{
+				Type:     "library",
+				BOMRef:   "pkg:golang/github.com/overridden/mod@v0.1.0",
+				Name:     "github.com/overridden/mod",
+				Version:  "v0.1.0",
+				Licenses: []cyclonedx.LicenseChoice{{License: &cyclonedx.License{ID: "BSD-3-Clause"}}},
+				PURL:     "pkg:golang/github.com/overridden/mod@v0.1.0",
+				Properties: []cyclonedx.Property{
+					{Name: "lichen:decision", Value: "licenses-not-allowed"},
+					{Name: "lichen:notPermitted", Value: "BSD-3-Clause"},
+					{Name: "lichen:severity", Value: "error"},
+					{Name: "lichen:rule", Value: "override github.com/overridden/mod"},
+				},
+			},
+		},
+		{
+			name: "licenses not on the SPDX license list are named",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/facebook/mod", Version: "v1.0.0"},
+					Licenses: []model.License{
+						{Name: "Facebook-3-Clause", Path: "/mod/LICENSE", Confidence: 1},
+						{Name: "MIT", Confidence: 1},
+					},
+				},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: cyclonedx.Component{
+				Type:    "library",
+				BOMRef:  "pkg:golang/github.com/facebook/mod@v1.0.0",
+				Name:    "github.com/facebook/mod",
+				Version: "v1.0.0",
+				Licenses: []cyclonedx.LicenseChoice{
+					{License: &cyclonedx.License{Name: "Facebook-3-Clause"}},
+					{License: &cyclonedx.License{ID: "MIT"}},
+				},
+				PURL:       "pkg:golang/github.com/facebook/mod@v1.0.0",
+				Properties: []cyclonedx.Property{{Name: "lichen:decision", Value: "allowed"}},
+				Evidence: &cyclonedx.Evidence{
+					Licenses: []cyclonedx.LicenseChoice{
+						{License: &cyclonedx.License{Name: "Facebook-3-Clause", Properties: []cyclonedx.Property{
+							{Name: "lichen:confidence", Value: "1.00"},
+							{Name: "lichen:path", Value: "/mod/LICENSE"},
+						}}},
+					},
+					Occurrences: []cyclonedx.Occurrence{{Location: "/mod/LICENSE"}},
+				},
+			},
+		},
+		{
+			name: "unversioned main module",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/acme/foo", Version: "(devel)"},
+					Main:            true,
+				},
+				Decision: scan.DecisionNotAllowedNoLicenseFiles,
+				Severity: scan.SeverityError,
+			},
+			expected: cyclonedx.Component{
+				Type:    "library",
+				BOMRef:  "module:github.com/acme/foo@(devel)",
+				Name:    "github.com/acme/foo",
+				Version: "(devel)",
+				Properties: []cyclonedx.Property{
+					{Name: "lichen:decision", Value: "no-license-files"},
+					{Name: "lichen:severity", Value: "error"},
+					{Name: "lichen:main", Value: "true"},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := tc.module
+			m.UsedBy = []string{foo.Path}
+			bom, err := cyclonedx.Combined(scan.Summary{Binaries: []model.BuildInfo{foo}, Modules: []scan.EvaluatedModule{m}}, time.Now())
+			require.NoError(t, err)
+
+			require.Len(t, bom.Components, 1)
+			assert.Equal(t, tc.expected, bom.Components[0])
+			assert.Equal(t, []cyclonedx.Dependency{{Ref: "binary:/bin/foo", DependsOn: []string{tc.expected.BOMRef}}}, bom.Dependencies)
+		})
+	}
+}
+
+func TestCombined_BOM(t *testing.T) {
+	bom, err := cyclonedx.Combined(scan.Summary{Binaries: []model.BuildInfo{foo, bar}}, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, bom.SerialNumber)
+	assert.Equal(t, "2023-06-01T12:00:00Z", bom.Metadata.Timestamp)
+	assert.Nil(t, bom.Metadata.Component)
+	assert.Equal(t, []cyclonedx.Component{
+		{
+			Type:       "application",
+			BOMRef:     "binary:/bin/foo",
+			Name:       "foo",
+			Version:    "v1.0.0",
+			Properties: []cyclonedx.Property{{Name: "lichen:package", Value: "github.com/acme/foo/cmd/foo"}},
+		},
+		{
+			Type:       "application",
+			BOMRef:     "binary:/bin/bar",
+			Name:       "bar",
+			Version:    "(devel)",
+			Properties: []cyclonedx.Property{{Name: "lichen:package", Value: "github.com/acme/bar"}},
+		},
+	}, bom.Components)
+}
+
+func TestPerBinary(t *testing.T) {
+	module := func(path string, usedBy ...string) scan.EvaluatedModule {
+		return scan.EvaluatedModule{
+			Module:   model.Module{ModuleReference: model.ModuleReference{Path: path, Version: "v1.0.0"}},
+			Decision: scan.DecisionAllowed,
+			UsedBy:   usedBy,
+		}
+	}
+	testCases := []struct {
+		name     string
+		modules  []scan.EvaluatedModule
+		expected [][]string // names of the components in the BOM of each binary
+	}{
+		{
+			name: "modules only included alongside the binaries using them",
+			modules: []scan.EvaluatedModule{
+				module("github.com/shared/mod", foo.Path, bar.Path),
+				module("github.com/bar/only", bar.Path),
+			},
+			expected: [][]string{
+				{"github.com/shared/mod"},
+				{"github.com/shared/mod", "github.com/bar/only"},
+			},
+		},
+		{
+			name:     "binary using no modules",
+			modules:  []scan.EvaluatedModule{module("github.com/bar/only", bar.Path)},
+			expected: [][]string{nil, {"github.com/bar/only"}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			boms, err := cyclonedx.PerBinary(scan.Summary{Binaries: []model.BuildInfo{foo, bar}, Modules: tc.modules}, time.Now())
+			require.NoError(t, err)
+			require.Len(t, boms, 2)
+
+			for i, bin := range []model.BuildInfo{foo, bar} {
+				require.NotNil(t, boms[i].Metadata.Component)
+				assert.Equal(t, "binary:"+bin.Path, boms[i].Metadata.Component.BOMRef)
+				var names []string
+				for _, c := range boms[i].Components {
+					names = append(names, c.Name)
+				}
+				assert.Equal(t, tc.expected[i], names)
+				require.Len(t, boms[i].Dependencies, 1)
+				assert.Len(t, boms[i].Dependencies[0].DependsOn, len(tc.expected[i]))
+			}
+			assert.NotEqual(t, boms[0].SerialNumber, boms[1].SerialNumber)
+		})
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	bom := cyclonedx.BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:00000000-0000-4000-8000-000000000000",
+		Version:      1,
+		Metadata: cyclonedx.Metadata{
+			Timestamp: "2023-06-01T12:00:00Z",
+			Tools:     cyclonedx.Tools{Components: []cyclonedx.Component{{Type: "application", Name: "lichen"}}},
+		},
+		Components: []cyclonedx.Component{{
+			Type:    "library",
+			BOMRef:  "pkg:golang/github.com/abc/xyz@v1.0.0",
+			Name:    "github.com/abc/xyz",
+			Version: "v1.0.0",
+			Licenses: []cyclonedx.LicenseChoice{
+				{License: &cyclonedx.License{ID: "MIT"}},
+				{License: &cyclonedx.License{Name: "Acme Commercial"}},
+			},
+			PURL:       "pkg:golang/github.com/abc/xyz@v1.0.0",
+			Properties: []cyclonedx.Property{{Name: "lichen:decision", Value: "allowed"}},
+		}},
+		Dependencies: []cyclonedx.Dependency{
+			{Ref: "binary:/bin/foo", DependsOn: []string{"pkg:golang/github.com/abc/xyz@v1.0.0"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cyclonedx.WriteXML(&buf, bom))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:00000000-0000-4000-8000-000000000000" version="1">
+  <metadata>
+    <timestamp>2023-06-01T12:00:00Z</timestamp>
+    <tools>
+      <components>
+        <component type="application">
+          <name>lichen</name>
+        </component>
+      </components>
+    </tools>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="pkg:golang/github.com/abc/xyz@v1.0.0">
+      <name>github.com/abc/xyz</name>
+      <version>v1.0.0</version>
+      <licenses>
+        <license>
+          <id>MIT</id>
+        </license>
+        <license>
+          <name>Acme Commercial</name>
+        </license>
+      </licenses>
+      <purl>pkg:golang/github.com/abc/xyz@v1.0.0</purl>
+      <properties>
+        <property name="lichen:decision">allowed</property>
+      </properties>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="binary:/bin/foo">
+      <dependency ref="pkg:golang/github.com/abc/xyz@v1.0.0"></dependency>
+    </dependency>
+  </dependencies>
+</bom>
+`, buf.String())
+}
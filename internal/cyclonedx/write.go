@@ -0,0 +1,212 @@
+package cyclonedx
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+)
+
+// WriteJSON writes the BOM in the CycloneDX JSON format
+func WriteJSON(w io.Writer, bom BOM) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bom)
+}
+
+// WriteXML writes the BOM in the CycloneDX XML format
+func WriteXML(w io.Writer, bom BOM) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(toXML(bom)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// the XML representation differs from the JSON representation in how lists and license choices are nested, so the
+// BOM is converted to the types below before encoding
+
+type xmlBOM struct {
+	XMLName      xml.Name         `xml:"bom"`
+	XMLNS        string           `xml:"xmlns,attr"`
+	SerialNumber string           `xml:"serialNumber,attr"`
+	Version      int              `xml:"version,attr"`
+	Metadata     xmlMetadata      `xml:"metadata"`
+	Components   *xmlComponents   `xml:"components,omitempty"`
+	Dependencies *xmlDependencies `xml:"dependencies,omitempty"`
+}
+
+type xmlMetadata struct {
+	Timestamp string         `xml:"timestamp"`
+	Tools     []xmlComponent `xml:"tools>components>component"`
+	Component *xmlComponent  `xml:"component,omitempty"`
+}
+
+type xmlComponent struct {
+	Type       string         `xml:"type,attr"`
+	BOMRef     string         `xml:"bom-ref,attr,omitempty"`
+	Name       string         `xml:"name"`
+	Version    string         `xml:"version,omitempty"`
+	Hashes     *xmlHashes     `xml:"hashes,omitempty"`
+	Licenses   *xmlLicenses   `xml:"licenses,omitempty"`
+	PURL       string         `xml:"purl,omitempty"`
+	Properties *xmlProperties `xml:"properties,omitempty"`
+	Evidence   *xmlEvidence   `xml:"evidence,omitempty"`
+}
+
+// encoding/xml writes empty parent elements for "a>b" paths, so lists are wrapped in their own types, which are
+// omitted when nil
+
+type xmlComponents struct {
+	Components []xmlComponent `xml:"component"`
+}
+
+type xmlDependencies struct {
+	Dependencies []xmlDependency `xml:"dependency"`
+}
+
+type xmlHashes struct {
+	Hashes []xmlHash `xml:"hash"`
+}
+
+type xmlProperties struct {
+	Properties []xmlProperty `xml:"property"`
+}
+
+type xmlOccurrences struct {
+	Occurrences []xmlOccurrence `xml:"occurrence"`
+}
+
+type xmlOccurrence struct {
+	Location string `xml:"location"`
+}
+
+type xmlHash struct {
+	Algorithm string `xml:"alg,attr"`
+	Content   string `xml:",chardata"`
+}
+
+type xmlLicenses struct {
+	Licenses   []xmlLicense `xml:"license,omitempty"`
+	Expression string       `xml:"expression,omitempty"`
+}
+
+type xmlLicense struct {
+	ID         string         `xml:"id,omitempty"`
+	Name       string         `xml:"name,omitempty"`
+	Properties *xmlProperties `xml:"properties,omitempty"`
+}
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlEvidence struct {
+	Licenses    *xmlLicenses    `xml:"licenses,omitempty"`
+	Occurrences *xmlOccurrences `xml:"occurrences,omitempty"`
+}
+
+type xmlDependency struct {
+	Ref       string          `xml:"ref,attr"`
+	DependsOn []xmlDependency `xml:"dependency,omitempty"`
+}
+
+func toXML(bom BOM) xmlBOM {
+	x := xmlBOM{
+		XMLNS:        xmlns,
+		SerialNumber: bom.SerialNumber,
+		Version:      bom.Version,
+		Metadata: xmlMetadata{
+			Timestamp: bom.Metadata.Timestamp,
+		},
+	}
+	for _, c := range bom.Metadata.Tools.Components {
+		x.Metadata.Tools = append(x.Metadata.Tools, componentToXML(c))
+	}
+	if c := bom.Metadata.Component; c != nil {
+		xc := componentToXML(*c)
+		x.Metadata.Component = &xc
+	}
+	if len(bom.Components) > 0 {
+		x.Components = &xmlComponents{}
+		for _, c := range bom.Components {
+			x.Components.Components = append(x.Components.Components, componentToXML(c))
+		}
+	}
+	if len(bom.Dependencies) > 0 {
+		x.Dependencies = &xmlDependencies{}
+		for _, d := range bom.Dependencies {
+			xd := xmlDependency{Ref: d.Ref}
+			for _, ref := range d.DependsOn {
+				xd.DependsOn = append(xd.DependsOn, xmlDependency{Ref: ref})
+			}
+			x.Dependencies.Dependencies = append(x.Dependencies.Dependencies, xd)
+		}
+	}
+	return x
+}
+
+func componentToXML(c Component) xmlComponent {
+	x := xmlComponent{
+		Type:       c.Type,
+		BOMRef:     c.BOMRef,
+		Name:       c.Name,
+		Version:    c.Version,
+		Licenses:   licensesToXML(c.Licenses),
+		PURL:       c.PURL,
+		Properties: propertiesToXML(c.Properties),
+	}
+	if len(c.Hashes) > 0 {
+		x.Hashes = &xmlHashes{}
+		for _, h := range c.Hashes {
+			x.Hashes.Hashes = append(x.Hashes.Hashes, xmlHash{Algorithm: h.Algorithm, Content: h.Content})
+		}
+	}
+	if e := c.Evidence; e != nil {
+		x.Evidence = &xmlEvidence{Licenses: licensesToXML(e.Licenses)}
+		if len(e.Occurrences) > 0 {
+			x.Evidence.Occurrences = &xmlOccurrences{}
+			for _, o := range e.Occurrences {
+				x.Evidence.Occurrences.Occurrences = append(x.Evidence.Occurrences.Occurrences, xmlOccurrence{Location: o.Location})
+			}
+		}
+	}
+	return x
+}
+
+func licensesToXML(choices []LicenseChoice) *xmlLicenses {
+	if len(choices) == 0 {
+		return nil
+	}
+	var x xmlLicenses
+	for _, choice := range choices {
+		if choice.Expression != "" {
+			x.Expression = choice.Expression
+			continue
+		}
+		if l := choice.License; l != nil {
+			x.Licenses = append(x.Licenses, xmlLicense{
+				ID:         l.ID,
+				Name:       l.Name,
+				Properties: propertiesToXML(l.Properties),
+			})
+		}
+	}
+	return &x
+}
+
+func propertiesToXML(properties []Property) *xmlProperties {
+	if len(properties) == 0 {
+		return nil
+	}
+	var x xmlProperties
+	for _, p := range properties {
+		x.Properties = append(x.Properties, xmlProperty{Name: p.Name, Value: p.Value})
+	}
+	return &x
+}
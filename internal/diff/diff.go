@@ -0,0 +1,187 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/uw-labs/lichen/internal/scan"
+	"golang.org/x/mod/semver"
+)
+
+// Diff describes the differences between the modules of two scans
+type Diff struct {
+	Added   []Module `json:",omitempty"`
+	Removed []Module `json:",omitempty"`
+	Changed []Change `json:",omitempty"`
+}
+
+// Empty returns true if the scans have no differences
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Module summarises a module of a scan
+type Module struct {
+	Path     string
+	Version  string
+	Licenses []string `json:",omitempty"`
+	Decision scan.Decision
+}
+
+// ChangeKind identifies an aspect of a module that changed between scans
+type ChangeKind string
+
+const (
+	ChangeKindUpgraded        ChangeKind = "upgraded"
+	ChangeKindDowngraded      ChangeKind = "downgraded"
+	ChangeKindVersionChanged  ChangeKind = "version-changed" // versions that can't be ordered, e.g. (devel)
+	ChangeKindLicensesChanged ChangeKind = "licenses-changed"
+	ChangeKindDecisionChanged ChangeKind = "decision-changed"
+)
+
+// Change describes a module present in both scans whose version, licenses or decision changed
+type Change struct {
+	Path  string
+	Kinds []ChangeKind
+	Old   Module
+	New   Module
+}
+
+// Has returns true if the change is of the supplied kind
+func (c Change) Has(kind ChangeKind) bool {
+	for _, k := range c.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the differences between the old and new scans. Modules are matched by path, and then by version:
+// where a module path is used at a single version in each scan that differs, the module is considered to have been
+// upgraded (or downgraded). Where a path is used at several versions, versions present in only one of the scans are
+// reported as added or removed.
+func Compare(old, new scan.Summary) Diff {
+	oldByPath, newByPath := byPath(old), byPath(new)
+
+	var d Diff
+	for path, oldMods := range oldByPath {
+		newMods := newByPath[path]
+		oldOnly, newOnly := make([]Module, 0, len(oldMods)), make([]Module, 0, len(newMods))
+		for _, o := range oldMods {
+			if n, found := findVersion(newMods, o.Version); found {
+				if c := compareModules(o, n); len(c.Kinds) > 0 {
+					d.Changed = append(d.Changed, c)
+				}
+			} else {
+				oldOnly = append(oldOnly, o)
+			}
+		}
+		for _, n := range newMods {
+			if _, found := findVersion(oldMods, n.Version); !found {
+				newOnly = append(newOnly, n)
+			}
+		}
+		if len(oldOnly) == 1 && len(newOnly) == 1 {
+			if c := compareModules(oldOnly[0], newOnly[0]); len(c.Kinds) > 0 {
+				d.Changed = append(d.Changed, c)
+			}
+			continue
+		}
+		d.Removed = append(d.Removed, oldOnly...)
+		d.Added = append(d.Added, newOnly...)
+	}
+	for path, newMods := range newByPath {
+		if _, found := oldByPath[path]; !found {
+			d.Added = append(d.Added, newMods...)
+		}
+	}
+
+	sortModules(d.Added)
+	sortModules(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool {
+		if d.Changed[i].Path != d.Changed[j].Path {
+			return d.Changed[i].Path < d.Changed[j].Path
+		}
+		return semver.Compare(d.Changed[i].Old.Version, d.Changed[j].Old.Version) < 0
+	})
+	return d
+}
+
+// byPath groups the modules of a scan by path
+func byPath(summary scan.Summary) map[string][]Module {
+	grouped := make(map[string][]Module)
+	for _, m := range summary.Modules {
+		mod := Module{
+			Path:     m.Path,
+			Version:  m.Version,
+			Decision: m.Decision,
+		}
+		for _, lic := range m.Licenses {
+			if !contains(mod.Licenses, lic.Name) {
+				mod.Licenses = append(mod.Licenses, lic.Name)
+			}
+		}
+		grouped[m.Path] = append(grouped[m.Path], mod)
+	}
+	return grouped
+}
+
+func findVersion(mods []Module, version string) (Module, bool) {
+	for _, m := range mods {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return Module{}, false
+}
+
+// compareModules returns the changes between two versions of a module
+func compareModules(old, new Module) Change {
+	c := Change{Path: old.Path, Old: old, New: new}
+	switch cmp := semver.Compare(old.Version, new.Version); {
+	case cmp < 0:
+		c.Kinds = append(c.Kinds, ChangeKindUpgraded)
+	case cmp > 0:
+		c.Kinds = append(c.Kinds, ChangeKindDowngraded)
+	case old.Version != new.Version:
+		c.Kinds = append(c.Kinds, ChangeKindVersionChanged)
+	}
+	if !sameLicenses(old.Licenses, new.Licenses) {
+		c.Kinds = append(c.Kinds, ChangeKindLicensesChanged)
+	}
+	if old.Decision != new.Decision {
+		c.Kinds = append(c.Kinds, ChangeKindDecisionChanged)
+	}
+	return c
+}
+
+// sameLicenses returns true if both lists contain the same licenses, regardless of order
+func sameLicenses(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, lic := range a {
+		if !contains(b, lic) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortModules(mods []Module) {
+	sort.Slice(mods, func(i, j int) bool {
+		if mods[i].Path != mods[j].Path {
+			return mods[i].Path < mods[j].Path
+		}
+		return semver.Compare(mods[i].Version, mods[j].Version) < 0
+	})
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/diff"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+func evaluated(path, version string, decision scan.Decision, licenses ...string) scan.EvaluatedModule {
+	m := scan.EvaluatedModule{
+		Module:   model.Module{ModuleReference: model.ModuleReference{Path: path, Version: version}},
+		Decision: decision,
+	}
+	for _, lic := range licenses {
+		m.Licenses = append(m.Licenses, model.License{Name: lic})
+	}
+	return m
+}
+
+func TestCompare(t *testing.T) {
+	old := scan.Summary{Modules: []scan.EvaluatedModule{
+		evaluated("github.com/removed/mod", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/upgraded/mod", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/relicensed/mod", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/unchanged/mod", "v1.0.0", scan.DecisionAllowed, "MIT", "Apache-2.0"),
+		evaluated("github.com/multi/mod", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/multi/mod", "v2.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/local/mod", "(devel)", scan.DecisionAllowed, "MIT"),
+	}}
+	new := scan.Summary{Modules: []scan.EvaluatedModule{
+		evaluated("github.com/added/mod", "v0.1.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/upgraded/mod", "v1.1.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/relicensed/mod", "v1.0.0", scan.DecisionNotAllowedLicenseNotPermitted, "GPL-3.0"),
+		evaluated("github.com/unchanged/mod", "v1.0.0", scan.DecisionAllowed, "Apache-2.0", "MIT"),
+		evaluated("github.com/multi/mod", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/multi/mod", "v2.1.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/multi/mod", "v2.2.0", scan.DecisionAllowed, "MIT"),
+		evaluated("github.com/local/mod", "(devel)+dirty", scan.DecisionAllowed, "MIT"),
+	}}
+
+	expected := diff.Diff{
+		Added: []diff.Module{
+			{Path: "github.com/added/mod", Version: "v0.1.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+			{Path: "github.com/multi/mod", Version: "v2.1.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+			{Path: "github.com/multi/mod", Version: "v2.2.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+		},
+		Removed: []diff.Module{
+			{Path: "github.com/multi/mod", Version: "v2.0.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+			{Path: "github.com/removed/mod", Version: "v1.0.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+		},
+		Changed: []diff.Change{
+			{
+				Path:  "github.com/local/mod",
+				Kinds: []diff.ChangeKind{diff.ChangeKindVersionChanged},
+				Old:   diff.Module{Path: "github.com/local/mod", Version: "(devel)", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+				New:   diff.Module{Path: "github.com/local/mod", Version: "(devel)+dirty", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+			},
+			{
+				Path:  "github.com/relicensed/mod",
+				Kinds: []diff.ChangeKind{diff.ChangeKindLicensesChanged, diff.ChangeKindDecisionChanged},
+				Old:   diff.Module{Path: "github.com/relicensed/mod", Version: "v1.0.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+				New:   diff.Module{Path: "github.com/relicensed/mod", Version: "v1.0.0", Licenses: []string{"GPL-3.0"}, Decision: scan.DecisionNotAllowedLicenseNotPermitted},
+			},
+			{
+				Path:  "github.com/upgraded/mod",
+				Kinds: []diff.ChangeKind{diff.ChangeKindUpgraded},
+				Old:   diff.Module{Path: "github.com/upgraded/mod", Version: "v1.0.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+				New:   diff.Module{Path: "github.com/upgraded/mod", Version: "v1.1.0", Licenses: []string{"MIT"}, Decision: scan.DecisionAllowed},
+			},
+		},
+	}
+	assert.Equal(t, expected, diff.Compare(old, new))
+}
+
+func TestWriteText(t *testing.T) {
+	d := diff.Compare(
+		scan.Summary{Modules: []scan.EvaluatedModule{
+			evaluated("github.com/foo/bar", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		}},
+		scan.Summary{Modules: []scan.EvaluatedModule{
+			evaluated("github.com/foo/bar", "v0.9.0", scan.DecisionNotAllowedNoLicenseFiles),
+			evaluated("github.com/baz/qux", "v1.0.0", scan.DecisionAllowed, "MIT"),
+		}},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteText(&buf, d))
+	assert.Equal(t, `+ github.com/baz/qux@v1.0.0: MIT (allowed)
+~ github.com/foo/bar@v1.0.0 -> v0.9.0: downgraded, licenses MIT -> none, decision allowed -> no-license-files
+`, buf.String())
+}
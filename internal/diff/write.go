@@ -0,0 +1,122 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// WriteText writes the diff as plain text, one line per module, prefixed with + (added), - (removed) or ~ (changed)
+func WriteText(w io.Writer, d Diff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "no differences")
+		return err
+	}
+	for _, m := range d.Added {
+		if _, err := fmt.Fprintf(w, "+ %s@%s: %s (%s)\n", m.Path, m.Version, licenses(m), decision(m.Decision)); err != nil {
+			return err
+		}
+	}
+	for _, m := range d.Removed {
+		if _, err := fmt.Fprintf(w, "- %s@%s: %s (%s)\n", m.Path, m.Version, licenses(m), decision(m.Decision)); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Changed {
+		details := make([]string, 0, len(c.Kinds))
+		for _, kind := range c.Kinds {
+			switch kind {
+			case ChangeKindLicensesChanged:
+				details = append(details, fmt.Sprintf("licenses %s -> %s", licenses(c.Old), licenses(c.New)))
+			case ChangeKindDecisionChanged:
+				details = append(details, fmt.Sprintf("decision %s -> %s", decision(c.Old.Decision), decision(c.New.Decision)))
+			default:
+				details = append(details, string(kind))
+			}
+		}
+		if _, err := fmt.Fprintf(w, "~ %s@%s: %s\n", c.Path, versions(c), strings.Join(details, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the diff as JSON
+func WriteJSON(w io.Writer, d Diff) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteMarkdown writes the diff as Markdown tables, suitable for release notes or pull request comments
+func WriteMarkdown(w io.Writer, d Diff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "No differences.")
+		return err
+	}
+	var b strings.Builder
+	for _, section := range []struct {
+		title   string
+		modules []Module
+	}{
+		{title: "Added", modules: d.Added},
+		{title: "Removed", modules: d.Removed},
+	} {
+		if len(section.modules) == 0 {
+			continue
+		}
+		writeTableHeader(&b, section.title)
+		for _, m := range section.modules {
+			writeTableRow(&b, m.Path, m.Version, licenses(m), decision(m.Decision))
+		}
+	}
+	if len(d.Changed) > 0 {
+		writeTableHeader(&b, "Changed")
+		for _, c := range d.Changed {
+			lics, dec := licenses(c.New), decision(c.New.Decision)
+			if c.Has(ChangeKindLicensesChanged) {
+				lics = fmt.Sprintf("%s → %s", licenses(c.Old), lics)
+			}
+			if c.Has(ChangeKindDecisionChanged) {
+				dec = fmt.Sprintf("%s → %s", decision(c.Old.Decision), dec)
+			}
+			writeTableRow(&b, c.Path, strings.Replace(versions(c), "->", "→", 1), lics, dec)
+		}
+	}
+	_, err := io.WriteString(w, strings.TrimPrefix(b.String(), "\n"))
+	return err
+}
+
+func writeTableHeader(b *strings.Builder, title string) {
+	fmt.Fprintf(b, "\n## %s\n\n| Module | Version | Licenses | Decision |\n| --- | --- | --- | --- |\n", title)
+}
+
+func writeTableRow(b *strings.Builder, cells ...string) {
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+}
+
+func licenses(m Module) string {
+	if len(m.Licenses) == 0 {
+		return "none"
+	}
+	return strings.Join(m.Licenses, ", ")
+}
+
+func decision(d scan.Decision) string {
+	text, _ := d.MarshalText()
+	return string(text)
+}
+
+// versions describes the versions of a changed module, e.g. "v1.0.0 -> v1.1.0", or "v1.0.0" if unchanged
+func versions(c Change) string {
+	if c.Old.Version == c.New.Version {
+		return c.Old.Version
+	}
+	return fmt.Sprintf("%s -> %s", c.Old.Version, c.New.Version)
+}
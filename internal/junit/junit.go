@@ -0,0 +1,104 @@
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// TestSuites is a JUnit XML report, with a test suite for each binary
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite covers the modules used by a binary
+type TestSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Cases    []TestCase `xml:"testcase"`
+}
+
+// TestCase is an evaluated module, which fails if the module is not allowed
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// New returns a report with a test suite for each binary, with a test case for each module the binary uses. Where
+// policies are scoped to binaries, each test case reflects the decision for its binary.
+func New(summary scan.Summary) (TestSuites, error) {
+	report := TestSuites{
+		Name:   "lichen",
+		Suites: make([]TestSuite, 0, len(summary.Binaries)),
+	}
+	for _, bin := range summary.Binaries {
+		suite := TestSuite{Name: bin.Path}
+		for _, m := range summary.Modules {
+			if !m.IsUsedBy(bin.Path) {
+				continue
+			}
+			m := forBinary(m, bin.Path)
+			tc := TestCase{
+				Name:      m.ModuleReference.String(),
+				ClassName: bin.Path,
+				SystemOut: licenses(m),
+			}
+			if !m.Allowed() {
+				decision, err := m.Decision.MarshalText()
+				if err != nil {
+					return TestSuites{}, err
+				}
+				explanation := m.ExplainDecision()
+				tc.Failure = &Failure{Message: explanation, Type: string(decision), Text: explanation}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+	return report, nil
+}
+
+// forBinary returns the module with the decision made by the policy of the binary, if policies are scoped to binaries
+func forBinary(m scan.EvaluatedModule, binPath string) scan.EvaluatedModule {
+	for _, bd := range m.Binaries {
+		if bd.Binary == binPath {
+			m.Decision, m.NotPermitted, m.Severity = bd.Decision, bd.NotPermitted, bd.Severity
+		}
+	}
+	return m
+}
+
+// licenses describes the licenses of the module, one per line
+func licenses(m scan.EvaluatedModule) string {
+	if len(m.Licenses) == 0 {
+		return "no licenses detected"
+	}
+	lines := make([]string, 0, len(m.Licenses))
+	for _, lic := range m.Licenses {
+		line := fmt.Sprintf("%s (confidence %.2f", lic.Name, lic.Confidence)
+		if lic.Path != "" {
+			line += ", " + lic.Path
+		}
+		lines = append(lines, line+")")
+	}
+	return strings.Join(lines, "\n")
+}
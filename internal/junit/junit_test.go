@@ -0,0 +1,190 @@
+package junit_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/junit"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+func TestNew(t *testing.T) {
+	ref := model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}
+	failure := func(decision, explanation string) *junit.Failure {
+		return &junit.Failure{Message: explanation, Type: decision, Text: explanation}
+	}
+
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		expected map[string]junit.TestCase // test case in the suite of each binary
+	}{
+		{
+			name: "allowed",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "MIT", Path: "/mod/LICENSE", Confidence: 0.98}}},
+				Decision: scan.DecisionAllowed,
+				UsedBy:   []string{"/bin/tool", "/bin/server"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/tool":   {SystemOut: "MIT (confidence 0.98, /mod/LICENSE)"},
+				"/bin/server": {SystemOut: "MIT (confidence 0.98, /mod/LICENSE)"},
+			},
+		},
+		{
+			name: "not used by every binary",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "GPL-3.0", Path: "/mod/LICENSE", Confidence: 1}}},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+				UsedBy:       []string{"/bin/server"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/server": {
+					Failure:   failure("licenses-not-allowed", "not allowed - non-permitted licenses: [GPL-3.0]"),
+					SystemOut: "GPL-3.0 (confidence 1.00, /mod/LICENSE)",
+				},
+			},
+		},
+		{
+			name: "policies scoped to binaries",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "GPL-3.0", Path: "/mod/LICENSE", Confidence: 1}}},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/tool": {SystemOut: "GPL-3.0 (confidence 1.00, /mod/LICENSE)"},
+				"/bin/server": {
+					Failure:   failure("licenses-not-allowed", "not allowed - non-permitted licenses: [GPL-3.0]"),
+					SystemOut: "GPL-3.0 (confidence 1.00, /mod/LICENSE)",
+				},
+			},
+		},
+		{
+			name: "policies scoped to binaries, with different decisions",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref},
+				Decision: scan.DecisionNotAllowedModuleDenied,
+				Severity: scan.SeverityError,
+				Rules:    []scan.Rule{{Type: scan.RuleTypeModuleDeny, Policy: "tools", Path: "github.com/abc/*"}},
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionNotAllowedModuleDenied, Severity: scan.SeverityError},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedNoLicenseFiles, Severity: scan.SeverityWarn},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/tool": {
+					Failure:   failure("module-denied", "not allowed - module denied by rule for github.com/abc/*"),
+					SystemOut: "no licenses detected",
+				},
+				"/bin/server": {
+					Failure:   failure("no-license-files", "not allowed - no license files found"),
+					SystemOut: "no licenses detected",
+				},
+			},
+		},
+		{
+			name: "licenses set by override",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "BSD-3-Clause", Confidence: 1}, {Name: "Acme Commercial", Confidence: 1}}},
+				Decision: scan.DecisionAllowed,
+				Rules:    []scan.Rule{{Type: scan.RuleTypeOverride, Path: "github.com/abc/xyz"}},
+				UsedBy:   []string{"/bin/tool"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/tool": {SystemOut: "BSD-3-Clause (confidence 1.00)\nAcme Commercial (confidence 1.00)"},
+			},
+		},
+		{
+			name: "baselined",
+			module: scan.EvaluatedModule{
+				Module:    model.Module{ModuleReference: ref},
+				Decision:  scan.DecisionNotAllowedModuleUnavailable,
+				Severity:  scan.SeverityError,
+				Baselined: true,
+				UsedBy:    []string{"/bin/tool"},
+			},
+			expected: map[string]junit.TestCase{
+				"/bin/tool": {
+					Failure:   failure("module-unavailable", "not allowed - module source unavailable"),
+					SystemOut: "no licenses detected",
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := junit.New(scan.Summary{
+				Binaries: []model.BuildInfo{{Path: "/bin/tool"}, {Path: "/bin/server"}},
+				Modules:  []scan.EvaluatedModule{tc.module},
+			})
+			require.NoError(t, err)
+
+			require.Len(t, report.Suites, 2)
+			var tests, failures int
+			for _, suite := range report.Suites {
+				expected, found := tc.expected[suite.Name]
+				if !found {
+					assert.Empty(t, suite.Cases, "suite %s", suite.Name)
+					continue
+				}
+				expected.Name, expected.ClassName = ref.String(), suite.Name
+				assert.Equal(t, []junit.TestCase{expected}, suite.Cases, "suite %s", suite.Name)
+				assert.Equal(t, 1, suite.Tests)
+				if expected.Failure != nil {
+					assert.Equal(t, 1, suite.Failures)
+					failures++
+				}
+				tests++
+			}
+			assert.Equal(t, tests, report.Tests)
+			assert.Equal(t, failures, report.Failures)
+		})
+	}
+}
+
+func TestWriteXML(t *testing.T) {
+	report := junit.TestSuites{
+		Name:     "lichen",
+		Tests:    1,
+		Failures: 1,
+		Suites: []junit.TestSuite{{
+			Name:     "/bin/foo",
+			Tests:    1,
+			Failures: 1,
+			Cases: []junit.TestCase{{
+				Name:      "github.com/def/uvw@v0.3.0",
+				ClassName: "/bin/foo",
+				Failure:   &junit.Failure{Message: "not allowed - no license files found", Type: "no-license-files", Text: "not allowed - no license files found"},
+				SystemOut: "no licenses detected",
+			}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, junit.WriteXML(&buf, report))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="lichen" tests="1" failures="1">
+  <testsuite name="/bin/foo" tests="1" failures="1">
+    <testcase name="github.com/def/uvw@v0.3.0" classname="/bin/foo">
+      <failure message="not allowed - no license files found" type="no-license-files">not allowed - no license files found</failure>
+      <system-out>no licenses detected</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,20 @@
+package junit
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// WriteXML writes the report in the JUnit XML format
+func WriteXML(w io.Writer, report TestSuites) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
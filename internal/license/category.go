@@ -0,0 +1,183 @@
+package license
+
+// Category groups licenses that carry broadly similar obligations
+type Category string
+
+const (
+	CategoryPermissive      Category = "permissive"
+	CategoryWeakCopyleft    Category = "weak-copyleft"
+	CategoryStrongCopyleft  Category = "strong-copyleft"
+	CategoryNetworkCopyleft Category = "network-copyleft"
+	CategoryPublicDomain    Category = "public-domain"
+	CategoryProprietary     Category = "proprietary"
+)
+
+// Categories lists all built-in categories
+var Categories = []Category{
+	CategoryPermissive,
+	CategoryWeakCopyleft,
+	CategoryStrongCopyleft,
+	CategoryNetworkCopyleft,
+	CategoryPublicDomain,
+	CategoryProprietary,
+}
+
+// categories maps the license names produced during classification to their built-in category
+var categories = map[string]Category{
+	"0BSD":                             CategoryPublicDomain,
+	"AFL-1.1":                          CategoryPermissive,
+	"AFL-1.2":                          CategoryPermissive,
+	"AFL-2.0":                          CategoryPermissive,
+	"AFL-2.1":                          CategoryPermissive,
+	"AFL-3.0":                          CategoryPermissive,
+	"AGPL-1.0":                         CategoryNetworkCopyleft,
+	"AGPL-3.0":                         CategoryNetworkCopyleft,
+	"APSL-1.0":                         CategoryWeakCopyleft,
+	"APSL-1.1":                         CategoryWeakCopyleft,
+	"APSL-1.2":                         CategoryWeakCopyleft,
+	"APSL-2.0":                         CategoryWeakCopyleft,
+	"Apache-1.0":                       CategoryPermissive,
+	"Apache-1.1":                       CategoryPermissive,
+	"Apache-2.0":                       CategoryPermissive,
+	"Artistic-1.0":                     CategoryPermissive,
+	"Artistic-1.0-Perl":                CategoryPermissive,
+	"Artistic-1.0-cl8":                 CategoryPermissive,
+	"Artistic-2.0":                     CategoryPermissive,
+	"BCL":                              CategoryProprietary,
+	"BSD-2-Clause":                     CategoryPermissive,
+	"BSD-2-Clause-FreeBSD":             CategoryPermissive,
+	"BSD-2-Clause-NetBSD":              CategoryPermissive,
+	"BSD-3-Clause":                     CategoryPermissive,
+	"BSD-3-Clause-Attribution":         CategoryPermissive,
+	"BSD-3-Clause-Clear":               CategoryPermissive,
+	"BSD-3-Clause-LBNL":                CategoryPermissive,
+	"BSD-4-Clause":                     CategoryPermissive,
+	"BSD-4-Clause-UC":                  CategoryPermissive,
+	"BSD-Protection":                   CategoryPermissive,
+	"BSL-1.0":                          CategoryPermissive,
+	"Beerware":                         CategoryPermissive,
+	"CC-BY-1.0":                        CategoryPermissive,
+	"CC-BY-2.0":                        CategoryPermissive,
+	"CC-BY-2.5":                        CategoryPermissive,
+	"CC-BY-3.0":                        CategoryPermissive,
+	"CC-BY-4.0":                        CategoryPermissive,
+	"CC-BY-NC-1.0":                     CategoryProprietary,
+	"CC-BY-NC-2.0":                     CategoryProprietary,
+	"CC-BY-NC-2.5":                     CategoryProprietary,
+	"CC-BY-NC-3.0":                     CategoryProprietary,
+	"CC-BY-NC-4.0":                     CategoryProprietary,
+	"CC-BY-NC-ND-1.0":                  CategoryProprietary,
+	"CC-BY-NC-ND-2.0":                  CategoryProprietary,
+	"CC-BY-NC-ND-2.5":                  CategoryProprietary,
+	"CC-BY-NC-ND-3.0":                  CategoryProprietary,
+	"CC-BY-NC-ND-4.0":                  CategoryProprietary,
+	"CC-BY-NC-SA-1.0":                  CategoryProprietary,
+	"CC-BY-NC-SA-2.0":                  CategoryProprietary,
+	"CC-BY-NC-SA-2.5":                  CategoryProprietary,
+	"CC-BY-NC-SA-3.0":                  CategoryProprietary,
+	"CC-BY-NC-SA-4.0":                  CategoryProprietary,
+	"CC-BY-ND-1.0":                     CategoryProprietary,
+	"CC-BY-ND-2.0":                     CategoryProprietary,
+	"CC-BY-ND-2.5":                     CategoryProprietary,
+	"CC-BY-ND-3.0":                     CategoryProprietary,
+	"CC-BY-ND-4.0":                     CategoryProprietary,
+	"CC-BY-SA-1.0":                     CategoryStrongCopyleft,
+	"CC-BY-SA-2.0":                     CategoryStrongCopyleft,
+	"CC-BY-SA-2.5":                     CategoryStrongCopyleft,
+	"CC-BY-SA-3.0":                     CategoryStrongCopyleft,
+	"CC-BY-SA-4.0":                     CategoryStrongCopyleft,
+	"CC0-1.0":                          CategoryPublicDomain,
+	"CDDL-1.0":                         CategoryWeakCopyleft,
+	"CDDL-1.1":                         CategoryWeakCopyleft,
+	"CPAL-1.0":                         CategoryNetworkCopyleft,
+	"CPL-1.0":                          CategoryWeakCopyleft,
+	"Commons-Clause":                   CategoryProprietary,
+	"EPL-1.0":                          CategoryWeakCopyleft,
+	"EPL-2.0":                          CategoryWeakCopyleft,
+	"EUPL-1.0":                         CategoryStrongCopyleft,
+	"EUPL-1.1":                         CategoryStrongCopyleft,
+	"FTL":                              CategoryPermissive,
+	"Facebook-2-Clause":                CategoryProprietary,
+	"Facebook-3-Clause":                CategoryProprietary,
+	"Facebook-Examples":                CategoryProprietary,
+	"FreeImage":                        CategoryWeakCopyleft,
+	"GPL-1.0":                          CategoryStrongCopyleft,
+	"GPL-2.0":                          CategoryStrongCopyleft,
+	"GPL-2.0-with-GCC-exception":       CategoryStrongCopyleft,
+	"GPL-2.0-with-autoconf-exception":  CategoryStrongCopyleft,
+	"GPL-2.0-with-bison-exception":     CategoryStrongCopyleft,
+	"GPL-2.0-with-classpath-exception": CategoryStrongCopyleft,
+	"GPL-2.0-with-font-exception":      CategoryStrongCopyleft,
+	"GPL-3.0":                          CategoryStrongCopyleft,
+	"GPL-3.0-with-GCC-exception":       CategoryStrongCopyleft,
+	"GPL-3.0-with-autoconf-exception":  CategoryStrongCopyleft,
+	"GUST-Font-License":                CategoryPermissive,
+	"IPL-1.0":                          CategoryWeakCopyleft,
+	"ISC":                              CategoryPermissive,
+	"ImageMagick":                      CategoryPermissive,
+	"LGPL-2.0":                         CategoryWeakCopyleft,
+	"LGPL-2.1":                         CategoryWeakCopyleft,
+	"LGPL-3.0":                         CategoryWeakCopyleft,
+	"LGPLLR":                           CategoryWeakCopyleft,
+	"LPL-1.0":                          CategoryPermissive,
+	"LPL-1.02":                         CategoryPermissive,
+	"LPPL-1.3c":                        CategoryPermissive,
+	"Libpng":                           CategoryPermissive,
+	"Lil-1.0":                          CategoryPermissive,
+	"Linux-OpenIB":                     CategoryPermissive,
+	"MIT":                              CategoryPermissive,
+	"MPL-1.0":                          CategoryWeakCopyleft,
+	"MPL-1.1":                          CategoryWeakCopyleft,
+	"MPL-2.0":                          CategoryWeakCopyleft,
+	"MPL-2.0-no-copyleft-exception":    CategoryWeakCopyleft,
+	"MS-PL":                            CategoryPermissive,
+	"NCSA":                             CategoryPermissive,
+	"NPL-1.0":                          CategoryWeakCopyleft,
+	"NPL-1.1":                          CategoryWeakCopyleft,
+	"OFL-1.1":                          CategoryWeakCopyleft,
+	"OSL-1.0":                          CategoryStrongCopyleft,
+	"OSL-1.1":                          CategoryStrongCopyleft,
+	"OSL-2.0":                          CategoryStrongCopyleft,
+	"OSL-2.1":                          CategoryStrongCopyleft,
+	"OSL-3.0":                          CategoryStrongCopyleft,
+	"OpenSSL":                          CategoryPermissive,
+	"OpenVision":                       CategoryPermissive,
+	"PHP-3.0":                          CategoryPermissive,
+	"PHP-3.01":                         CategoryPermissive,
+	"PIL":                              CategoryPermissive,
+	"PostgreSQL":                       CategoryPermissive,
+	"Python-2.0":                       CategoryPermissive,
+	"Python-2.0-complete":              CategoryPermissive,
+	"QPL-1.0":                          CategoryStrongCopyleft,
+	"Ruby":                             CategoryWeakCopyleft,
+	"SGI-B-1.0":                        CategoryPermissive,
+	"SGI-B-1.1":                        CategoryPermissive,
+	"SGI-B-2.0":                        CategoryPermissive,
+	"SISSL":                            CategoryWeakCopyleft,
+	"SISSL-1.2":                        CategoryWeakCopyleft,
+	"Sleepycat":                        CategoryStrongCopyleft,
+	"UPL-1.0":                          CategoryPermissive,
+	"Unicode-DFS-2015":                 CategoryPermissive,
+	"Unicode-DFS-2016":                 CategoryPermissive,
+	"Unicode-TOU":                      CategoryPermissive,
+	"Unlicense":                        CategoryPublicDomain,
+	"W3C":                              CategoryPermissive,
+	"W3C-19980720":                     CategoryPermissive,
+	"W3C-20150513":                     CategoryPermissive,
+	"WTFPL":                            CategoryPublicDomain,
+	"X11":                              CategoryPermissive,
+	"Xnet":                             CategoryPermissive,
+	"ZPL-1.1":                          CategoryPermissive,
+	"ZPL-2.0":                          CategoryPermissive,
+	"ZPL-2.1":                          CategoryPermissive,
+	"Zend-2.0":                         CategoryPermissive,
+	"Zlib":                             CategoryPermissive,
+	"eGenix":                           CategoryPermissive,
+	"zlib-acknowledgement":             CategoryPermissive,
+}
+
+// CategoryOf returns the built-in category of the named license, or an empty category if the license has not been
+// categorised
+func CategoryOf(name string) Category {
+	return categories[name]
+}
@@ -0,0 +1,58 @@
+package license_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uw-labs/lichen/internal/license"
+)
+
+func TestCategoryOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected license.Category
+	}{
+		{name: "MIT", expected: license.CategoryPermissive},
+		{name: "LGPL-3.0", expected: license.CategoryWeakCopyleft},
+		{name: "GPL-2.0", expected: license.CategoryStrongCopyleft},
+		{name: "AGPL-3.0", expected: license.CategoryNetworkCopyleft},
+		{name: "Unlicense", expected: license.CategoryPublicDomain},
+		{name: "CC-BY-NC-4.0", expected: license.CategoryProprietary},
+		{name: "Not-A-License", expected: ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, license.CategoryOf(tc.name))
+		})
+	}
+}
+
+func TestCategoryOf_AllNamesCategorised(t *testing.T) {
+	names, err := license.Names()
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	for _, name := range names {
+		assert.NotEmpty(t, license.CategoryOf(name), "license %s has no category", name)
+	}
+}
+
+func TestIsSPDX(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "MIT", expected: true},
+		{name: "GPL-2.0-with-classpath-exception", expected: true},
+		{name: "Facebook-3-Clause", expected: false},
+		{name: "Proprietary", expected: false},
+		{name: "LicenseRef-Acme", expected: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, license.IsSPDX(tc.name))
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package license
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/license/db"
+)
+
+// Names returns the sorted names of all licenses that can be produced by classification
+func Names() ([]string, error) {
+	f, err := db.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open license database: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read license database: %w", err)
+	}
+	defer gz.Close()
+
+	unique := make(map[string]struct{})
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to read license database: %w", err)
+		}
+		// each license has a text and a hash entry, optionally accompanied by header variants
+		if !strings.HasSuffix(hdr.Name, ".txt") {
+			continue
+		}
+		unique[strings.TrimSuffix(strings.TrimSuffix(hdr.Name, ".txt"), ".header")] = struct{}{}
+	}
+
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
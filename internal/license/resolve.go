@@ -12,9 +12,42 @@ import (
 	"github.com/uw-labs/lichen/internal/model"
 )
 
+// floor is the confidence below which license matches are discarded altogether, rather than retained as below the
+// threshold. It is lowered to any threshold that is lower still.
+const floor = 0.50
+
+// Thresholds are the minimum confidences required for license matches to count
+type Thresholds struct {
+	Default  float64
+	Licenses map[string]float64 // overrides the default for particular licenses
+}
+
+// For returns the threshold applicable to the named license
+func (t Thresholds) For(name string) float64 {
+	if threshold, found := t.Licenses[name]; found {
+		return threshold
+	}
+	return t.Default
+}
+
+// min returns the lowest of the thresholds and the floor, below which matches are discarded altogether
+func (t Thresholds) min() float64 {
+	min := floor
+	if t.Default < min {
+		min = t.Default
+	}
+	for _, threshold := range t.Licenses {
+		if threshold < min {
+			min = threshold
+		}
+	}
+	return min
+}
+
 // Resolve inspects each module and determines what it is licensed under. The returned slice contains each
-// module enriched with license information.
-func Resolve(modules []model.Module, threshold float64) ([]model.Module, error) {
+// module enriched with license information. Matches with a confidence below the threshold applicable to the license
+// are retained separately, rather than counting towards the module's licenses.
+func Resolve(modules []model.Module, thresholds Thresholds) ([]model.Module, error) {
 	archiveFn := licenseclassifier.ArchiveFunc(func() ([]byte, error) {
 		f, err := db.Open()
 		if err != nil {
@@ -24,16 +57,16 @@ func Resolve(modules []model.Module, threshold float64) ([]model.Module, error)
 		return ioutil.ReadAll(f)
 	})
 
-	lc, err := licenseclassifier.New(threshold, archiveFn)
+	lc, err := licenseclassifier.New(thresholds.min(), archiveFn)
 	if err != nil {
 		return nil, err
 	}
 
 	for i, m := range modules {
-		if m.IsLocal() {
+		if m.IsLocal() || m.Dir == "" {
 			// there is no guarantee we are being run in a location that makes local module references resolvable.. to
 			// avoid incidental and non-obvious behaviour here, we simply don't touch such references - overrides must
-			// be provided instead.
+			// be provided instead. The same applies to modules whose source could not be located.
 			continue
 		}
 		paths, err := locateLicenses(m.Dir)
@@ -44,7 +77,19 @@ func Resolve(modules []model.Module, threshold float64) ([]model.Module, error)
 		if err != nil {
 			return nil, err
 		}
-		m.Licenses = licenses
+		notices, err := readNotices(m.Dir)
+		if err != nil {
+			return nil, err
+		}
+		m.LicenseFiles, m.NoticeFiles = paths, notices
+		m.Licenses, m.BelowThreshold = make([]model.License, 0, len(licenses)), nil
+		for _, lic := range licenses {
+			if lic.Confidence < thresholds.For(lic.Name) {
+				m.BelowThreshold = append(m.BelowThreshold, lic)
+			} else {
+				m.Licenses = append(m.Licenses, lic)
+			}
+		}
 		modules[i] = m
 	}
 
@@ -67,6 +112,29 @@ func locateLicenses(path string) (lp []string, err error) {
 	return lp, nil
 }
 
+var noticeRgx = regexp.MustCompile(`(?i)^notice`)
+
+// readNotices reads any NOTICE files, which are distinct from license files but must be reproduced alongside them
+func readNotices(path string) ([]model.NoticeFile, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var notices []model.NoticeFile
+	for _, f := range files {
+		if f.IsDir() || !noticeRgx.MatchString(f.Name()) || strings.HasSuffix(f.Name(), ".go") {
+			continue
+		}
+		p := filepath.Join(path, f.Name())
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		notices = append(notices, model.NoticeFile{Path: p, Content: string(content)})
+	}
+	return notices, nil
+}
+
 // classify inspects each license file and classifies it
 func classify(lc *licenseclassifier.License, paths []string) ([]model.License, error) {
 	licenses := make([]model.License, 0)
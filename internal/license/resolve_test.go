@@ -0,0 +1,78 @@
+package license_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uw-labs/lichen/internal/license"
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+func TestResolve(t *testing.T) {
+	content, err := os.ReadFile("../../LICENSE")
+	require.NoError(t, err)
+	words := strings.Fields(string(content))
+
+	testCases := []struct {
+		name           string
+		words          int // number of words of the MIT license in the license file
+		thresholds     license.Thresholds
+		resolved       []string
+		belowThreshold []string
+	}{
+		{
+			name:           "full text",
+			words:          len(words),
+			thresholds:     license.Thresholds{Default: 0.80},
+			resolved:       []string{"MIT"},
+			belowThreshold: []string{"X11"}, // the near-identical X11 license is a weaker match
+		},
+		{
+			name:           "partial text, below the default threshold",
+			words:          len(words) * 7 / 10,
+			thresholds:     license.Thresholds{Default: 0.80},
+			belowThreshold: []string{"MIT", "X11"},
+		},
+		{
+			name:           "partial text, above a lower threshold for the license",
+			words:          len(words) * 7 / 10,
+			thresholds:     license.Thresholds{Default: 0.80, Licenses: map[string]float64{"MIT": 0.65}},
+			resolved:       []string{"MIT"},
+			belowThreshold: []string{"X11"},
+		},
+		{
+			name:       "fragment, below the floor",
+			words:      len(words) / 3,
+			thresholds: license.Thresholds{Default: 0.80},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "LICENSE"), []byte(strings.Join(words[:tc.words], " ")), 0644))
+			modules := []model.Module{{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}, Dir: dir}}
+
+			resolved, err := license.Resolve(modules, tc.thresholds)
+			require.NoError(t, err)
+			require.Len(t, resolved, 1)
+			assert.ElementsMatch(t, tc.resolved, names(resolved[0].Licenses))
+			assert.ElementsMatch(t, tc.belowThreshold, names(resolved[0].BelowThreshold))
+			for _, lic := range resolved[0].BelowThreshold {
+				assert.Less(t, lic.Confidence, tc.thresholds.For(lic.Name))
+			}
+		})
+	}
+}
+
+func names(licenses []model.License) []string {
+	names := make([]string, 0, len(licenses))
+	for _, lic := range licenses {
+		names = append(names, lic.Name)
+	}
+	return names
+}
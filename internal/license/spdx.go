@@ -0,0 +1,21 @@
+package license
+
+// nonSPDX lists the license names produced during classification that aren't identifiers on the SPDX license list
+var nonSPDX = map[string]bool{
+	"BCL":                 true,
+	"Commons-Clause":      true,
+	"Facebook-2-Clause":   true,
+	"Facebook-3-Clause":   true,
+	"Facebook-Examples":   true,
+	"GUST-Font-License":   true,
+	"Lil-1.0":             true,
+	"OpenVision":          true,
+	"Python-2.0-complete": true,
+}
+
+// IsSPDX returns true if the named license can be produced by classification, and is identified by the same name on
+// the SPDX license list. Other names, e.g. those listed under custom categories, must be referenced with a
+// "LicenseRef-" identifier in SPDX license expressions.
+func IsSPDX(name string) bool {
+	return categories[name] != "" && !nonSPDX[name]
+}
@@ -0,0 +1,113 @@
+package match
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// RegexPrefix prefixes path patterns that are to be interpreted as regular expressions
+const RegexPrefix = "regex:"
+
+type pathKind int
+
+const (
+	pathExact pathKind = iota
+	pathGlob
+	pathRegex
+)
+
+// Path matches module paths. Patterns can take one of three forms:
+//   - an exact module path, e.g. github.com/foo/bar
+//   - a glob, where `*` matches any sequence of characters other than `/`, `**` matches any sequence of characters
+//     (including `/`) and `?` matches any single character other than `/`, e.g. github.com/foo/**
+//   - a regular expression prefixed with "regex:", which must match the entire module path, e.g. regex:golang\.org/x/.*
+type Path struct {
+	pattern string
+	kind    pathKind
+	rgx     *regexp.Regexp
+	literal int
+}
+
+// ParsePath parses the supplied path pattern
+func ParsePath(pattern string) (Path, error) {
+	if pattern == "" {
+		return Path{}, fmt.Errorf("empty path pattern")
+	}
+	if strings.HasPrefix(pattern, RegexPrefix) {
+		// anchor the expression as a whole, so that any alternative matching the entire path is a match
+		rgx, err := regexp.Compile(`^(?:` + strings.TrimPrefix(pattern, RegexPrefix) + `)$`)
+		if err != nil {
+			return Path{}, fmt.Errorf("invalid path regex %q: %w", pattern, err)
+		}
+		return Path{pattern: pattern, kind: pathRegex, rgx: rgx}, nil
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		return Path{pattern: pattern, kind: pathExact}, nil
+	}
+
+	var (
+		sb      strings.Builder
+		literal int
+	)
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+			literal++
+		}
+	}
+	sb.WriteString("$")
+	return Path{pattern: pattern, kind: pathGlob, rgx: regexp.MustCompile(sb.String()), literal: literal}, nil
+}
+
+// MustParsePath is like ParsePath, but panics if the pattern is invalid
+func MustParsePath(pattern string) Path {
+	p, err := ParsePath(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Match returns true if the module path matches the pattern
+func (p Path) Match(path string) bool {
+	switch p.kind {
+	case pathExact:
+		return p.pattern == path
+	case pathGlob, pathRegex:
+		return p.rgx.MatchString(path)
+	default:
+		return false
+	}
+}
+
+// Specificity returns a score that can be used to pick between multiple patterns matching the same path; the higher
+// the score, the more specific the pattern. Exact paths are more specific than globs, which are in turn more specific
+// than regular expressions. Globs with more literal characters are more specific than those with fewer.
+func (p Path) Specificity() int {
+	switch p.kind {
+	case pathExact:
+		return math.MaxInt32
+	case pathGlob:
+		return p.literal
+	default:
+		return -1
+	}
+}
+
+// String returns the pattern as originally supplied
+func (p Path) String() string {
+	return p.pattern
+}
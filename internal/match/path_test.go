@@ -0,0 +1,61 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/match"
+)
+
+func TestPath_Match(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{name: "exact match", pattern: "github.com/foo/bar", path: "github.com/foo/bar", expected: true},
+		{name: "exact mismatch", pattern: "github.com/foo/bar", path: "github.com/foo/baz", expected: false},
+		{name: "exact prefix", pattern: "github.com/foo", path: "github.com/foo/bar", expected: false},
+		{name: "single star", pattern: "github.com/foo/*", path: "github.com/foo/bar", expected: true},
+		{name: "single star nested", pattern: "github.com/foo/*", path: "github.com/foo/bar/v2", expected: false},
+		{name: "double star nested", pattern: "github.com/foo/**", path: "github.com/foo/bar/v2", expected: true},
+		{name: "double star parent", pattern: "github.com/foo/**", path: "github.com/foo", expected: false},
+		{name: "star within segment", pattern: "golang.org/x/*", path: "golang.org/x/sys", expected: true},
+		{name: "question mark", pattern: "gopkg.in/yaml.v?", path: "gopkg.in/yaml.v2", expected: true},
+		{name: "dots are literal", pattern: "github.com/foo/b.r*", path: "github.com/foo/bxr", expected: false},
+		{name: "regex", pattern: `regex:^golang\.org/x/(sys|mod)$`, path: "golang.org/x/mod", expected: true},
+		{name: "regex is anchored", pattern: `regex:golang\.org/x/sys`, path: "golang.org/x/sys/v2", expected: false},
+		{name: "regex alternation", pattern: `regex:github\.com/a|github\.com/a/b`, path: "github.com/a/b", expected: true},
+		{name: "regex alternation anchored", pattern: `regex:github\.com/a|github\.com/b`, path: "github.com/a/b", expected: false},
+		{name: "regex mismatch", pattern: `regex:golang\.org/x/(sys|mod)`, path: "golang.org/x/net", expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := match.ParsePath(tc.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p.Match(tc.path))
+		})
+	}
+}
+
+func TestPath_Specificity(t *testing.T) {
+	exact := match.MustParsePath("github.com/foo/bar")
+	narrowGlob := match.MustParsePath("github.com/foo/*")
+	wideGlob := match.MustParsePath("github.com/**")
+	rgx := match.MustParsePath("regex:^github.com/foo/bar$")
+
+	assert.Greater(t, exact.Specificity(), narrowGlob.Specificity())
+	assert.Greater(t, narrowGlob.Specificity(), wideGlob.Specificity())
+	assert.Greater(t, wideGlob.Specificity(), rgx.Specificity())
+}
+
+func TestParsePath_Invalid(t *testing.T) {
+	_, err := match.ParsePath("regex:(")
+	assert.Error(t, err)
+
+	_, err = match.ParsePath("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Version matches module versions against a constraint. Constraints can take the following forms:
+//   - empty, "*" or "any", matching all versions
+//   - an exact version, e.g. v1.2.3 (versions that aren't valid semver, such as "(devel)", are only matched exactly)
+//   - one or more space separated comparisons that must all hold, e.g. ">=v1.2.0 <v2.0.0", using the operators
+//     =, !=, >, >=, < and <=
+//   - a tilde range, permitting patch level changes, e.g. ~v0.4 or ~v0.4.2 (>=v0.4.2 <v0.5.0)
+//   - a caret range, permitting changes that don't modify the left-most non-zero component, e.g. ^v1.2 (>=v1.2.0 <v2.0.0)
+//   - alternatives separated by "||", any of which must hold, e.g. "~v1.2 || >=v1.4.1"
+//
+// The "v" prefix is optional. Pseudo-versions are ordered relative to the release they are based on, so
+// v1.2.4-0.20200101000000-abcdef123456 (a commit following v1.2.3) satisfies ">v1.2.3 <v1.2.4". Ranges covering a
+// whole major version (e.g. ~v0 or ^v2) include pseudo-versions of commits preceding its first release.
+type Version struct {
+	constraint string
+	any        bool
+	exact      bool
+	sets       [][]comparison
+}
+
+type comparison struct {
+	op      string
+	version string
+}
+
+// ParseVersion parses the supplied version constraint
+func ParseVersion(constraint string) (Version, error) {
+	constraint = strings.TrimSpace(constraint)
+	v := Version{constraint: constraint}
+	switch {
+	case constraint == "" || constraint == "*" || constraint == "any":
+		v.any = true
+		return v, nil
+	case !strings.ContainsAny(constraint, "<>=!~^| "):
+		v.exact = true
+		return v, nil
+	}
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		var set []comparison
+		for _, term := range strings.Fields(alternative) {
+			cmps, err := parseTerm(term)
+			if err != nil {
+				return Version{}, fmt.Errorf("invalid version constraint %q: %w", constraint, err)
+			}
+			set = append(set, cmps...)
+		}
+		if len(set) == 0 {
+			return Version{}, fmt.Errorf("invalid version constraint %q: empty alternative", constraint)
+		}
+		v.sets = append(v.sets, set)
+	}
+	return v, nil
+}
+
+// MustParseVersion is like ParseVersion, but panics if the constraint is invalid
+func MustParseVersion(constraint string) Version {
+	v, err := ParseVersion(constraint)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// parseTerm parses a single term of a constraint into one or more comparisons
+func parseTerm(term string) ([]comparison, error) {
+	for _, prefix := range []string{"~", "^"} {
+		if strings.HasPrefix(term, prefix) {
+			return parseRange(prefix, strings.TrimPrefix(term, prefix))
+		}
+	}
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(term, op) {
+			version, err := canonical(strings.TrimPrefix(term, op))
+			if err != nil {
+				return nil, err
+			}
+			return []comparison{{op: op, version: version}}, nil
+		}
+	}
+	version, err := canonical(term)
+	if err != nil {
+		return nil, err
+	}
+	return []comparison{{op: "=", version: version}}, nil
+}
+
+// parseRange expands tilde and caret ranges into a lower (inclusive) and upper (exclusive) bound
+func parseRange(prefix, version string) ([]comparison, error) {
+	lower, err := canonical(version)
+	if err != nil {
+		return nil, err
+	}
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(semver.Canonical(lower), "v%d.%d.%d", &major, &minor, &patch); err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+	// the number of components specified determines the width of a tilde range, e.g. ~v1 permits minor changes
+	components := strings.Count(strings.SplitN(strings.TrimPrefix(version, "v"), "-", 2)[0], ".") + 1
+
+	var upper string
+	switch {
+	case prefix == "~" && components == 1:
+		upper = fmt.Sprintf("v%d.0.0", major+1)
+	case prefix == "~":
+		upper = fmt.Sprintf("v%d.%d.0", major, minor+1)
+	case major > 0 || components == 1:
+		upper = fmt.Sprintf("v%d.0.0", major+1)
+	case minor > 0 || components == 2:
+		upper = fmt.Sprintf("v0.%d.0", minor+1)
+	default:
+		upper = fmt.Sprintf("v0.0.%d", patch+1)
+	}
+	// a range covering an entire major version also covers pseudo-versions that predate its first release
+	if components == 1 && semver.Prerelease(lower) == "" {
+		lower += "-0"
+	}
+	// the upper bound is given the lowest possible prerelease, so that pseudo-versions based on commits preceding the
+	// upper bound release (e.g. v2.0.0-0.20200101000000-abcdef123456 when the upper bound is v2.0.0) are excluded
+	return []comparison{
+		{op: ">=", version: lower},
+		{op: "<", version: upper + "-0"},
+	}, nil
+}
+
+// canonical validates the version, adding a "v" prefix if required
+func canonical(version string) (string, error) {
+	if version == "" {
+		return "", fmt.Errorf("missing version")
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("invalid semantic version %q", version)
+	}
+	return version, nil
+}
+
+// Match returns true if the module version satisfies the constraint
+func (v Version) Match(version string) bool {
+	switch {
+	case v.any:
+		return true
+	case v.exact:
+		return v.constraint == version || "v"+v.constraint == version
+	case !semver.IsValid(version):
+		return false
+	}
+	for _, set := range v.sets {
+		if matchesAll(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(set []comparison, version string) bool {
+	for _, cmp := range set {
+		c := semver.Compare(version, cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = c == 0
+		case "!=":
+			ok = c != 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAny returns true if the constraint matches all versions
+func (v Version) IsAny() bool {
+	return v.any
+}
+
+// IsExact returns true if the constraint matches a single version
+func (v Version) IsExact() bool {
+	return v.exact
+}
+
+// String returns the constraint as originally supplied
+func (v Version) String() string {
+	return v.constraint
+}
@@ -0,0 +1,62 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/match"
+)
+
+func TestVersion_Match(t *testing.T) {
+	testCases := []struct {
+		name       string
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{name: "empty", constraint: "", version: "v1.0.0", expected: true},
+		{name: "any", constraint: "any", version: "(devel)", expected: true},
+		{name: "exact match", constraint: "v1.0.0", version: "v1.0.0", expected: true},
+		{name: "exact mismatch", constraint: "v1.0.0", version: "v1.0.1", expected: false},
+		{name: "exact without prefix", constraint: "1.0.0", version: "v1.0.0", expected: true},
+		{name: "exact incompatible", constraint: "v2.0.0+incompatible", version: "v2.0.0+incompatible", expected: true},
+		{name: "range within", constraint: ">=v1.2.0 <v2.0.0", version: "v1.9.3", expected: true},
+		{name: "range lower bound", constraint: ">=v1.2.0 <v2.0.0", version: "v1.2.0", expected: true},
+		{name: "range below", constraint: ">=v1.2.0 <v2.0.0", version: "v1.1.9", expected: false},
+		{name: "range upper bound", constraint: ">=v1.2.0 <v2.0.0", version: "v2.0.0", expected: false},
+		{name: "not equal", constraint: "!=v1.2.0", version: "v1.2.1", expected: true},
+		{name: "range invalid version", constraint: ">=v1.2.0", version: "(devel)", expected: false},
+		{name: "tilde minor", constraint: "~v0.4", version: "v0.4.9", expected: true},
+		{name: "tilde minor excluded", constraint: "~v0.4", version: "v0.5.0", expected: false},
+		{name: "tilde patch", constraint: "~v0.4.2", version: "v0.4.1", expected: false},
+		{name: "tilde major", constraint: "~v1", version: "v1.8.0", expected: true},
+		{name: "caret", constraint: "^v1.2", version: "v1.9.0", expected: true},
+		{name: "caret excluded", constraint: "^v1.2", version: "v2.0.0", expected: false},
+		{name: "caret zero major", constraint: "^v0.4.2", version: "v0.5.0", expected: false},
+		{name: "alternatives", constraint: "~v1.2 || >=v1.4.1", version: "v1.4.2", expected: true},
+		{name: "alternatives excluded", constraint: "~v1.2 || >=v1.4.1", version: "v1.3.0", expected: false},
+		{name: "pseudo-version after release", constraint: ">v1.2.3 <v1.2.4", version: "v1.2.4-0.20200101000000-abcdef123456", expected: true},
+		{name: "pseudo-version within tilde", constraint: "~v0.4", version: "v0.4.3-0.20200101000000-abcdef123456", expected: true},
+		{name: "pseudo-version without base", constraint: "~v0", version: "v0.0.0-20200101000000-abcdef123456", expected: true},
+		{name: "pseudo-version of next major", constraint: "~v1", version: "v2.0.0-20200101000000-abcdef123456", expected: false},
+		{name: "pseudo-version exact", constraint: "v0.0.0-20200101000000-abcdef123456", version: "v0.0.0-20200101000000-abcdef123456", expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := match.ParseVersion(tc.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v.Match(tc.version))
+		})
+	}
+}
+
+func TestParseVersion_Invalid(t *testing.T) {
+	for _, constraint := range []string{">=", ">=foo", "~bar", "v1 ||", ">=v1.0.0 <"} {
+		t.Run(constraint, func(t *testing.T) {
+			_, err := match.ParseVersion(constraint)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -3,21 +3,35 @@ package model
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 // BuildInfo encapsulates build info embedded into a Go compile binary
 type BuildInfo struct {
-	Path        string            // OS level absolute path to the binary this build info relates to
-	PackagePath string            // package path indicated by the build info, e.g. github.com/foo/bar/cmd/baz
-	ModulePath  string            // module path indicated by the build info, e.g. github.com/foo/bar
-	ModuleRefs  []ModuleReference // all modules that feature in the build info output
+	Path          string            // OS level absolute path to the binary this build info relates to
+	PackagePath   string            // package path indicated by the build info, e.g. github.com/foo/bar/cmd/baz
+	ModulePath    string            // module path indicated by the build info, e.g. github.com/foo/bar
+	ModuleVersion string            // version of the main module, "(devel)" if built from a source checkout
+	ModuleRefs    []ModuleReference // all modules that feature in the build info output
+}
+
+// MainModule returns a reference to the main module of the binary
+func (b BuildInfo) MainModule() ModuleReference {
+	return ModuleReference{Path: b.ModulePath, Version: b.ModuleVersion}
 }
 
 // Module carries details of a Go module
 type Module struct {
-	ModuleReference           // reference (path & version)
-	Dir             string    // OS level absolute path to where the cached copy of the module is located
-	Licenses        []License // resolved licenses
+	ModuleReference              // reference (path & version)
+	Dir             string       // OS level absolute path to where the cached copy of the module is located
+	Unavailable     string       `json:",omitempty"` // reason the module's source is unavailable, if it is (e.g. the error downloading it)
+	Sum             string       `json:",omitempty"` // checksum of the module contents, as recorded in go.sum (h1:...)
+	ZipSHA256       string       `json:",omitempty"` // hex encoded SHA-256 hash of the module's zip, as downloaded from the module proxy
+	Licenses        []License    // resolved licenses
+	BelowThreshold  []License    `json:",omitempty"` // license matches below the confidence threshold, which are not resolved
+	LicenseFiles    []string     `json:",omitempty"` // OS level absolute paths to the license files found in the module
+	NoticeFiles     []NoticeFile `json:",omitempty"` // NOTICE files found in the module, which must accompany its redistribution
+	Main            bool         `json:",omitempty"` // true if the module is the main module of a scanned binary
 }
 
 // ModuleReference is a reference to a particular version of a named module
@@ -44,6 +58,21 @@ func (r ModuleReference) String() string {
 	return fmt.Sprintf("%s@%s", r.Path, r.Version)
 }
 
+// PackageURL returns the purl of the module (pkg:golang/...), or an empty string if the module has no version, as is
+// the case for local modules and main modules built from a source checkout
+func (r ModuleReference) PackageURL() string {
+	if r.Version == "" || strings.HasPrefix(r.Version, "(") {
+		return ""
+	}
+	return fmt.Sprintf("pkg:golang/%s@%s", r.Path, strings.ReplaceAll(r.Version, "+", "%2B"))
+}
+
+// NoticeFile carries the contents of a NOTICE file, as distributed with modules under licenses such as Apache-2.0
+type NoticeFile struct {
+	Path    string // OS level absolute path to the NOTICE file
+	Content string // the exact contents of the NOTICE file
+}
+
 // License carries license classification details
 type License struct {
 	Path       string  // OS level absolute path to the license file
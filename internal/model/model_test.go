@@ -106,3 +106,50 @@ func TestModuleReference_IsLocal(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleReference_PackageURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		ref      model.ModuleReference
+		expected string
+	}{
+		{
+			name: "released version",
+			ref: model.ModuleReference{
+				Path:    "github.com/foo/bar",
+				Version: "v1.2.3",
+			},
+			expected: "pkg:golang/github.com/foo/bar@v1.2.3",
+		},
+		{
+			name: "incompatible version",
+			ref: model.ModuleReference{
+				Path:    "github.com/foo/bar",
+				Version: "v2.0.0+incompatible",
+			},
+			expected: "pkg:golang/github.com/foo/bar@v2.0.0%2Bincompatible",
+		},
+		{
+			name: "local module",
+			ref: model.ModuleReference{
+				Path: "./foo",
+			},
+			expected: "",
+		},
+		{
+			name: "source checkout",
+			ref: model.ModuleReference{
+				Path:    "github.com/foo/bar",
+				Version: "(devel)",
+			},
+			expected: "",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(tt *testing.T) {
+			actual := tc.ref.PackageURL()
+			assert.Equal(tt, tc.expected, actual)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package module
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +17,18 @@ import (
 	"github.com/uw-labs/lichen/internal/model"
 )
 
+// download is the output of `go mod download -json` for a single module
+type download struct {
+	Path    string
+	Version string
+	Dir     string
+	Sum     string
+	Zip     string // path of the downloaded zip
+	Error   string // set if the module could not be downloaded
+}
+
+// Fetch downloads the referenced modules, returning the directory of each, along with the hash of its zip. Modules that
+// could not be downloaded are returned without a directory, along with the error downloading them.
 func Fetch(ctx context.Context, refs []model.ModuleReference) ([]model.Module, error) {
 	if len(refs) == 0 {
 		return []model.Module{}, nil
@@ -38,23 +52,43 @@ func Fetch(ctx context.Context, refs []model.ModuleReference) ([]model.Module, e
 		}
 	}
 
+	// modules that fail to download are reported in the output with an error, and cause a non-zero exit code, which
+	// only fails the fetch if the output doesn't cover every module
 	cmd := exec.CommandContext(ctx, goBin, args...)
 	cmd.Dir = tempDir
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w (output: %s)", err, string(out))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, runErr := cmd.Output()
+	failed := func(err error) error {
+		if runErr != nil {
+			err = runErr
+		}
+		return fmt.Errorf("failed to fetch: %w (output: %s%s)", err, string(out), stderr.String())
 	}
 
 	// parse JSON output from `go mod download`
 	modules := make([]model.Module, 0)
 	dec := json.NewDecoder(bytes.NewReader(out))
 	for {
-		var m model.Module
-		if err := dec.Decode(&m); err != nil {
+		var d download
+		if err := dec.Decode(&d); err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			return nil, err
+			return nil, failed(err)
+		}
+		m := model.Module{
+			ModuleReference: model.ModuleReference{Path: d.Path, Version: d.Version},
+			Dir:             d.Dir,
+			Sum:             d.Sum,
+		}
+		if d.Error != "" {
+			// the module is returned without a directory, so that it is reported as unavailable
+			m.Dir, m.Sum, m.Unavailable = "", "", d.Error
+		} else if d.Zip != "" {
+			if m.ZipSHA256, err = hashFile(d.Zip); err != nil {
+				return nil, fmt.Errorf("failed to hash zip of module %s: %w", m.ModuleReference, err)
+			}
 		}
 		modules = append(modules, m)
 	}
@@ -70,12 +104,30 @@ func Fetch(ctx context.Context, refs []model.ModuleReference) ([]model.Module, e
 
 	// sanity check: all modules should have been covered in the output from `go mod download`
 	if err := verifyFetched(modules, refs); err != nil {
+		if runErr != nil {
+			return nil, failed(runErr)
+		}
 		return nil, fmt.Errorf("failed to fetch all modules: %w", err)
 	}
 
 	return modules, nil
 }
 
+// hashFile returns the hex encoded SHA-256 hash of the file at the supplied path
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func verifyFetched(fetched []model.Module, requested []model.ModuleReference) (err error) {
 	fetchedRefs := make(map[model.ModuleReference]struct{}, len(fetched))
 	for _, module := range fetched {
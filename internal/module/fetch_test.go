@@ -1,12 +1,20 @@
 package module_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/uw-labs/lichen/internal/model"
 	"github.com/uw-labs/lichen/internal/module"
+	mod "golang.org/x/mod/module"
+	modzip "golang.org/x/mod/zip"
 )
 
 func TestModuleFetchNoModules(test *testing.T) {
@@ -15,3 +23,48 @@ func TestModuleFetchNoModules(test *testing.T) {
 	assert.NoError(test, err)
 	assert.Empty(test, modules)
 }
+
+func TestModuleFetchUnavailable(test *testing.T) {
+	test.Setenv("GOPROXY", "off")
+	ref := model.ModuleReference{Path: "github.com/uw-labs/lichen-unavailable", Version: "v1.0.0"}
+
+	modules, err := module.Fetch(context.Background(), []model.ModuleReference{ref})
+
+	assert.NoError(test, err)
+	require.Len(test, modules, 1)
+	assert.Equal(test, ref, modules[0].ModuleReference)
+	assert.Empty(test, modules[0].Dir)
+	assert.Equal(test, "github.com/uw-labs/lichen-unavailable@v1.0.0: module lookup disabled by GOPROXY=off", modules[0].Unavailable)
+}
+
+func TestModuleFetchZipHash(test *testing.T) {
+	// serve a module from a file based proxy, with a fresh module cache
+	ref := model.ModuleReference{Path: "example.com/lichen/fetch", Version: "v1.0.0"}
+	src := test.TempDir()
+	require.NoError(test, os.WriteFile(filepath.Join(src, "go.mod"), []byte("module "+ref.Path+"\n"), 0644))
+	require.NoError(test, os.WriteFile(filepath.Join(src, "LICENSE"), []byte("MIT License"), 0644))
+
+	proxy := test.TempDir()
+	versions := filepath.Join(proxy, filepath.FromSlash(ref.Path), "@v")
+	require.NoError(test, os.MkdirAll(versions, 0755))
+	var zip bytes.Buffer
+	require.NoError(test, modzip.CreateFromDir(&zip, mod.Version{Path: ref.Path, Version: ref.Version}, src))
+	require.NoError(test, os.WriteFile(filepath.Join(versions, "v1.0.0.zip"), zip.Bytes(), 0644))
+	require.NoError(test, os.WriteFile(filepath.Join(versions, "v1.0.0.mod"), []byte("module "+ref.Path+"\n"), 0644))
+	require.NoError(test, os.WriteFile(filepath.Join(versions, "v1.0.0.info"), []byte(`{"Version":"v1.0.0"}`), 0644))
+	require.NoError(test, os.WriteFile(filepath.Join(versions, "list"), []byte("v1.0.0\n"), 0644))
+
+	test.Setenv("GOPROXY", "file://"+filepath.ToSlash(proxy))
+	test.Setenv("GOSUMDB", "off")
+	test.Setenv("GOMODCACHE", test.TempDir())
+	test.Setenv("GOFLAGS", "-modcacherw")
+
+	modules, err := module.Fetch(context.Background(), []model.ModuleReference{ref})
+
+	require.NoError(test, err)
+	require.Len(test, modules, 1)
+	hash := sha256.Sum256(zip.Bytes())
+	assert.Equal(test, hex.EncodeToString(hash[:]), modules[0].ZipSHA256)
+	assert.NotEmpty(test, modules[0].Dir)
+	assert.NotEmpty(test, modules[0].Sum)
+}
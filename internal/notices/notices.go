@@ -0,0 +1,132 @@
+package notices
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// Document is an attribution document, listing each module along with its licenses. Identical license texts are
+// included once, and referenced by each module they apply to.
+type Document struct {
+	Modules []Module
+	Texts   []Text
+}
+
+// Module is a module listed by the attribution document
+type Module struct {
+	model.ModuleReference
+	Licenses []string           // names of the module's licenses
+	Texts    []int              // IDs of the license texts of the module, empty if none were found
+	Notices  []model.NoticeFile // NOTICE files distributed with the module
+}
+
+// Text is a license text, shared by one or more modules
+type Text struct {
+	ID       int                     // identifies the text within the document, starting from 1
+	Licenses []string                // names of the licenses the text was classified as
+	Content  string                  // the license text
+	Modules  []model.ModuleReference // the modules the text applies to
+}
+
+// New returns an attribution document covering the third-party modules in the scan results, i.e. excluding the main
+// modules of the scanned binaries, unless they are also dependencies of another. Where an override set the licenses of
+// a module, the texts of the license files it replaced are included.
+func New(summary scan.Summary) Document {
+	var (
+		doc   Document
+		texts = make(map[string]int) // normalised content to index in doc.Texts
+		mains = make(map[string]model.ModuleReference, len(summary.Binaries))
+	)
+	for _, bin := range summary.Binaries {
+		mains[bin.Path] = bin.MainModule()
+	}
+	for _, m := range summary.Modules {
+		if mainOnly(m, mains) {
+			continue
+		}
+		mod := Module{ModuleReference: m.ModuleReference, Notices: m.NoticeFiles}
+		for _, lic := range m.Licenses {
+			mod.Licenses = appendUnique(mod.Licenses, lic.Name)
+		}
+		for _, lic := range append(append([]model.License(nil), m.Licenses...), m.Overridden...) {
+			if strings.TrimSpace(lic.Content) == "" {
+				continue
+			}
+			key := normalise(lic.Content)
+			i, found := texts[key]
+			if !found {
+				i = len(doc.Texts)
+				texts[key] = i
+				doc.Texts = append(doc.Texts, Text{ID: i + 1, Content: strings.TrimSpace(lic.Content)})
+			}
+			t := &doc.Texts[i]
+			t.Licenses = appendUnique(t.Licenses, lic.Name)
+			if !containsRef(t.Modules, m.ModuleReference) {
+				t.Modules = append(t.Modules, m.ModuleReference)
+			}
+			if !containsID(mod.Texts, t.ID) {
+				mod.Texts = append(mod.Texts, t.ID)
+			}
+		}
+		sort.Strings(mod.Licenses)
+		doc.Modules = append(doc.Modules, mod)
+	}
+	for i := range doc.Texts {
+		sort.Strings(doc.Texts[i].Licenses)
+	}
+	return doc
+}
+
+// mainOnly returns true if the module is the main module of each binary using it (keyed by path in mains), rather than
+// a dependency of any
+func mainOnly(m scan.EvaluatedModule, mains map[string]model.ModuleReference) bool {
+	if !m.Main {
+		return false
+	}
+	for _, bin := range m.UsedBy {
+		if mains[bin] != m.ModuleReference {
+			return false
+		}
+	}
+	return true
+}
+
+// normalise returns the license text with line endings and trailing whitespace normalised, so that texts differing
+// only in formatting are grouped together
+func normalise(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func containsRef(refs []model.ModuleReference, ref model.ModuleReference) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package notices_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/notices"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+var (
+	xyz = model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}
+	uvw = model.ModuleReference{Path: "github.com/def/uvw", Version: "v0.3.0"}
+	rst = model.ModuleReference{Path: "github.com/ghi/rst", Version: "v2.0.0"}
+)
+
+func TestNew(t *testing.T) {
+	app := model.ModuleReference{Path: "github.com/acme/app", Version: "v1.2.0"}
+	binaries := []model.BuildInfo{
+		{Path: "/bin/app", ModulePath: app.Path, ModuleVersion: app.Version, ModuleRefs: []model.ModuleReference{rst}},
+		{Path: "/bin/tool", ModulePath: "github.com/acme/tool", ModuleVersion: "(devel)", ModuleRefs: []model.ModuleReference{app, rst}},
+	}
+
+	testCases := []struct {
+		name     string
+		modules  []scan.EvaluatedModule
+		expected notices.Document
+	}{
+		{
+			name: "identical texts are shared",
+			modules: []scan.EvaluatedModule{
+				{Module: model.Module{
+					ModuleReference: xyz,
+					Licenses: []model.License{
+						{Name: "Apache-2.0", Path: "/xyz/LICENSE", Content: "Apache License\n"},
+						{Name: "MIT", Path: "/xyz/LICENSE", Content: "Apache License\n"},
+					},
+				}},
+				{Module: model.Module{
+					ModuleReference: uvw,
+					Licenses:        []model.License{{Name: "Apache-2.0", Path: "/uvw/LICENSE", Content: "Apache License   \r\n"}},
+				}},
+			},
+			expected: notices.Document{
+				Modules: []notices.Module{
+					{ModuleReference: xyz, Licenses: []string{"Apache-2.0", "MIT"}, Texts: []int{1}},
+					{ModuleReference: uvw, Licenses: []string{"Apache-2.0"}, Texts: []int{1}},
+				},
+				Texts: []notices.Text{
+					{ID: 1, Licenses: []string{"Apache-2.0", "MIT"}, Content: "Apache License", Modules: []model.ModuleReference{xyz, uvw}},
+				},
+			},
+		},
+		{
+			name: "texts of licenses replaced by override",
+			modules: []scan.EvaluatedModule{{
+				Module: model.Module{
+					ModuleReference: xyz,
+					Licenses:        []model.License{{Name: "BSD-3-Clause", Confidence: 1}},
+				},
+				Overridden: []model.License{{Name: "BSD-2-Clause", Path: "/xyz/LICENSE", Content: "Redistribution and use\n", Confidence: 0.7}},
+			}},
+			expected: notices.Document{
+				Modules: []notices.Module{{ModuleReference: xyz, Licenses: []string{"BSD-3-Clause"}, Texts: []int{1}}},
+				Texts: []notices.Text{
+					{ID: 1, Licenses: []string{"BSD-2-Clause"}, Content: "Redistribution and use", Modules: []model.ModuleReference{xyz}},
+				},
+			},
+		},
+		{
+			name: "override without license files",
+			modules: []scan.EvaluatedModule{{
+				Module: model.Module{ModuleReference: rst, Licenses: []model.License{{Name: "BSD-3-Clause", Confidence: 1}}},
+			}},
+			expected: notices.Document{
+				Modules: []notices.Module{{ModuleReference: rst, Licenses: []string{"BSD-3-Clause"}}},
+			},
+		},
+		{
+			name: "main modules are excluded",
+			modules: []scan.EvaluatedModule{
+				{Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/acme/app", Version: "(devel)"},
+					Licenses:        []model.License{{Name: "MIT", Path: "/app/LICENSE", Content: "MIT License"}},
+					NoticeFiles:     []model.NoticeFile{{Path: "/app/NOTICE", Content: "Copyright Acme"}},
+					Main:            true,
+				}},
+				{Module: model.Module{ModuleReference: rst}},
+			},
+			expected: notices.Document{
+				Modules: []notices.Module{{ModuleReference: rst}},
+			},
+		},
+		{
+			name: "main modules of the binaries using them are excluded",
+			modules: []scan.EvaluatedModule{
+				{
+					Module: model.Module{
+						ModuleReference: app,
+						Licenses:        []model.License{{Name: "MIT", Path: "/app/LICENSE", Content: "MIT License"}},
+						Main:            true,
+					},
+					UsedBy: []string{"/bin/app"},
+				},
+				{Module: model.Module{ModuleReference: rst}, UsedBy: []string{"/bin/app", "/bin/tool"}},
+			},
+			expected: notices.Document{
+				Modules: []notices.Module{{ModuleReference: rst}},
+			},
+		},
+		{
+			name: "main modules that are dependencies of another binary",
+			modules: []scan.EvaluatedModule{
+				{
+					Module: model.Module{
+						ModuleReference: app,
+						Licenses:        []model.License{{Name: "MIT", Path: "/app/LICENSE", Content: "MIT License"}},
+						Main:            true,
+					},
+					UsedBy: []string{"/bin/app", "/bin/tool"},
+				},
+				{Module: model.Module{ModuleReference: rst}, UsedBy: []string{"/bin/app", "/bin/tool"}},
+			},
+			expected: notices.Document{
+				Modules: []notices.Module{
+					{ModuleReference: app, Licenses: []string{"MIT"}, Texts: []int{1}},
+					{ModuleReference: rst},
+				},
+				Texts: []notices.Text{
+					{ID: 1, Licenses: []string{"MIT"}, Content: "MIT License", Modules: []model.ModuleReference{app}},
+				},
+			},
+		},
+		{
+			name: "notice files",
+			modules: []scan.EvaluatedModule{{
+				Module: model.Module{
+					ModuleReference: xyz,
+					NoticeFiles:     []model.NoticeFile{{Path: "/xyz/NOTICE", Content: "Copyright 2020 Xyz\n"}},
+				},
+			}},
+			expected: notices.Document{
+				Modules: []notices.Module{{ModuleReference: xyz, Notices: []model.NoticeFile{{Path: "/xyz/NOTICE", Content: "Copyright 2020 Xyz\n"}}}},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, notices.New(scan.Summary{Modules: tc.modules, Binaries: binaries}))
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	doc := notices.Document{
+		Modules: []notices.Module{
+			{
+				ModuleReference: xyz,
+				Licenses:        []string{"Apache-2.0", "MIT"},
+				Texts:           []int{1},
+				Notices:         []model.NoticeFile{{Path: "/xyz/NOTICE", Content: "Copyright 2020 Xyz\n"}},
+			},
+			{ModuleReference: uvw, Licenses: []string{"Apache-2.0"}, Texts: []int{1}},
+			{ModuleReference: rst, Licenses: []string{"BSD-3-Clause"}},
+		},
+		Texts: []notices.Text{
+			{ID: 1, Licenses: []string{"Apache-2.0", "MIT"}, Content: "Apache License", Modules: []model.ModuleReference{xyz, uvw}},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, notices.WriteText(&buf, doc))
+	sep := strings.Repeat("=", 80)
+	assert.Equal(t, `THIRD-PARTY NOTICES
+
+This software includes the following third-party modules, which are distributed under the licenses below.
+
+github.com/abc/xyz v1.0.0: Apache-2.0, MIT (license text 1)
+github.com/def/uvw v0.3.0: Apache-2.0 (license text 1)
+github.com/ghi/rst v2.0.0: BSD-3-Clause
+
+`+sep+`
+License text 1: Apache-2.0, MIT
+Used by: github.com/abc/xyz v1.0.0, github.com/def/uvw v0.3.0
+`+sep+`
+
+Apache License
+
+`+sep+`
+NOTICE for github.com/abc/xyz v1.0.0
+`+sep+`
+
+Copyright 2020 Xyz
+`, buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	doc := notices.Document{
+		Modules: []notices.Module{{ModuleReference: xyz, Licenses: []string{"MIT"}, Texts: []int{1}}},
+		Texts:   []notices.Text{{ID: 1, Licenses: []string{"MIT"}, Content: "Use ``` freely", Modules: []model.ModuleReference{xyz}}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, notices.WriteMarkdown(&buf, doc))
+	assert.Equal(t, "# Third-party notices\n\n"+
+		"This software includes the following third-party modules, which are distributed under the licenses below.\n\n"+
+		"| Module | Version | Licenses |\n| --- | --- | --- |\n"+
+		"| github.com/abc/xyz | v1.0.0 | MIT ([1](#license-text-1)) |\n\n"+
+		"## License texts\n\n### License text 1\n\nMIT, used by github.com/abc/xyz v1.0.0.\n\n"+
+		"````\nUse ``` freely\n````\n", buf.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	doc := notices.Document{
+		Modules: []notices.Module{{ModuleReference: xyz, Licenses: []string{"MIT"}, Texts: []int{1}}},
+		Texts:   []notices.Text{{ID: 1, Licenses: []string{"MIT"}, Content: "Copyright <Xyz> & co", Modules: []model.ModuleReference{xyz}}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, notices.WriteHTML(&buf, doc))
+	assert.Contains(t, buf.String(), `<td>MIT <a href="#license-text-1">[1]</a></td>`)
+	assert.Contains(t, buf.String(), `<pre>Copyright &lt;Xyz&gt; &amp; co</pre>`)
+	assert.NotContains(t, buf.String(), "<h2>Notices</h2>")
+}
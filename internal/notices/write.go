@@ -0,0 +1,179 @@
+package notices
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+const (
+	title     = "Third-party notices"
+	preamble  = "This software includes the following third-party modules, which are distributed under the licenses below."
+	separator = "================================================================================"
+)
+
+// WriteText writes the document as plain text
+func WriteText(w io.Writer, doc Document) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", strings.ToUpper(title), preamble)
+	for _, m := range doc.Modules {
+		fmt.Fprintf(&b, "%s %s: %s%s\n", m.Path, m.Version, licenses(m), textRefs(m))
+	}
+	for _, t := range doc.Texts {
+		fmt.Fprintf(&b, "\n%s\nLicense text %d: %s\nUsed by: %s\n%s\n\n%s\n", separator, t.ID, strings.Join(t.Licenses, ", "),
+			modules(t.Modules), separator, t.Content)
+	}
+	for _, m := range doc.Modules {
+		for _, n := range m.Notices {
+			fmt.Fprintf(&b, "\n%s\nNOTICE for %s %s\n%s\n\n%s\n", separator, m.Path, m.Version, separator, strings.TrimSpace(n.Content))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes the document as Markdown, with license texts and notices in code blocks
+func WriteMarkdown(w io.Writer, doc Document) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n%s\n\n| Module | Version | Licenses |\n| --- | --- | --- |\n", title, preamble)
+	for _, m := range doc.Modules {
+		refs := make([]string, 0, len(m.Texts))
+		for _, id := range m.Texts {
+			refs = append(refs, fmt.Sprintf("[%d](#license-text-%d)", id, id))
+		}
+		lics := licenses(m)
+		if len(refs) > 0 {
+			lics += " (" + strings.Join(refs, ", ") + ")"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", escapeCell(m.Path), escapeCell(m.Version), escapeCell(lics))
+	}
+	if len(doc.Texts) > 0 {
+		b.WriteString("\n## License texts\n")
+	}
+	for _, t := range doc.Texts {
+		fmt.Fprintf(&b, "\n### License text %d\n\n%s, used by %s.\n\n", t.ID, strings.Join(t.Licenses, ", "), modules(t.Modules))
+		writeCodeBlock(&b, t.Content)
+	}
+	if hasNotices(doc) {
+		b.WriteString("\n## Notices\n")
+	}
+	for _, m := range doc.Modules {
+		for _, n := range m.Notices {
+			fmt.Fprintf(&b, "\n### %s %s\n\n", m.Path, m.Version)
+			writeCodeBlock(&b, strings.TrimSpace(n.Content))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("notices").Funcs(template.FuncMap{
+	"join":    strings.Join,
+	"modules": modules,
+	"trim":    strings.TrimSpace,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+pre { background: #f6f8fa; padding: 1em; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Preamble}}</p>
+<table>
+<tr><th>Module</th><th>Version</th><th>Licenses</th></tr>
+{{- range .Doc.Modules}}
+<tr><td>{{.Path}}</td><td>{{.Version}}</td><td>{{if .Licenses}}{{join .Licenses ", "}}{{else}}none{{end}}{{range .Texts}} <a href="#license-text-{{.}}">[{{.}}]</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- if .Doc.Texts}}
+<h2>License texts</h2>
+{{- range .Doc.Texts}}
+<h3 id="license-text-{{.ID}}">License text {{.ID}}</h3>
+<p>{{join .Licenses ", "}}, used by {{modules .Modules}}.</p>
+<pre>{{.Content}}</pre>
+{{- end}}
+{{- end}}
+{{- if .HasNotices}}
+<h2>Notices</h2>
+{{- range .Doc.Modules}}{{$m := .}}{{range .Notices}}
+<h3>{{$m.Path}} {{$m.Version}}</h3>
+<pre>{{trim .Content}}</pre>
+{{- end}}{{end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the document as a standalone HTML page
+func WriteHTML(w io.Writer, doc Document) error {
+	return htmlTemplate.Execute(w, struct {
+		Title      string
+		Preamble   string
+		Doc        Document
+		HasNotices bool
+	}{
+		Title:      title,
+		Preamble:   preamble,
+		Doc:        doc,
+		HasNotices: hasNotices(doc),
+	})
+}
+
+func licenses(m Module) string {
+	if len(m.Licenses) == 0 {
+		return "none"
+	}
+	return strings.Join(m.Licenses, ", ")
+}
+
+// textRefs describes the license texts of the module, e.g. " (license text 1, 2)"
+func textRefs(m Module) string {
+	if len(m.Texts) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(m.Texts))
+	for _, id := range m.Texts {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return " (license text " + strings.Join(ids, ", ") + ")"
+}
+
+func modules(refs []model.ModuleReference) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, fmt.Sprintf("%s %s", ref.Path, ref.Version))
+	}
+	return strings.Join(names, ", ")
+}
+
+func hasNotices(doc Document) bool {
+	for _, m := range doc.Modules {
+		if len(m.Notices) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeCell(cell string) string {
+	return strings.ReplaceAll(cell, "|", `\|`)
+}
+
+// writeCodeBlock writes the content in a fenced code block, with a fence longer than any run of backticks it contains
+func writeCodeBlock(b *strings.Builder, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%s\n%s\n%s\n", fence, content, fence)
+}
@@ -0,0 +1,206 @@
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// Report is a view of the scan results, as presented by the HTML report
+type Report struct {
+	Created    string
+	Totals     Totals
+	Licenses   []LicenseTotals
+	Decisions  []DecisionTotals
+	Modules    []Module
+	Binaries   []Binary
+	StaleRules []scan.StaleRule
+}
+
+// Totals counts the modules by outcome
+type Totals struct {
+	Modules    int
+	Allowed    int
+	NotAllowed int
+	Failed     int
+	Baselined  int
+}
+
+// LicenseTotals counts the modules under a license, by whether they are allowed. Modules with several licenses are
+// counted under each of them.
+type LicenseTotals struct {
+	Name       string
+	Modules    int
+	Allowed    int
+	NotAllowed int
+}
+
+// DecisionTotals counts the modules with a decision
+type DecisionTotals struct {
+	Decision string
+	Modules  int
+}
+
+// Module is an evaluated module, as listed by the report
+type Module struct {
+	scan.EvaluatedModule
+	DecisionText string // the decision, as it appears in the JSON results
+}
+
+// LicenseNames returns the names of the module's licenses
+func (m Module) LicenseNames() string {
+	names := m.licenseNames()
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m Module) licenseNames() []string {
+	names := make([]string, 0, len(m.Licenses))
+	for _, lic := range m.Licenses {
+		names = appendUnique(names, lic.Name)
+	}
+	return names
+}
+
+// Search returns the text the module filter matches against: the module's path, version, license names and the rules
+// applied to it, lowercased. License texts are excluded, as most mention a variety of other licenses and terms.
+func (m Module) Search() string {
+	terms := append([]string{m.Path, m.Version}, m.licenseNames()...)
+	for _, rule := range m.Rules {
+		terms = append(terms, string(rule.Type), rule.Path, rule.Version, rule.Policy, rule.Justification)
+		terms = append(terms, rule.Licenses...)
+	}
+	return strings.ToLower(strings.Join(terms, " "))
+}
+
+// Binary is a scanned binary, along with the outcome for each module it uses
+type Binary struct {
+	model.BuildInfo
+	Modules    []BinaryModule
+	NotAllowed int
+}
+
+// BinaryModule is the outcome of evaluating a module for a particular binary
+type BinaryModule struct {
+	Module       model.ModuleReference
+	Licenses     string
+	DecisionText string
+	Explanation  string
+	Policy       string // name of the scoped policy applied, empty for the top-level config
+	Allowed      bool
+}
+
+// New returns a report of the scan results
+func New(summary scan.Summary, created time.Time) Report {
+	r := Report{
+		Created:    created.UTC().Format(time.RFC3339),
+		StaleRules: summary.StaleRules,
+	}
+
+	licenses := make(map[string]*LicenseTotals)
+	decisions := make(map[string]int)
+	for _, em := range summary.Modules {
+		m := Module{EvaluatedModule: em, DecisionText: decisionText(em.Decision)}
+		r.Modules = append(r.Modules, m)
+
+		r.Totals.Modules++
+		if m.Allowed() {
+			r.Totals.Allowed++
+		} else {
+			r.Totals.NotAllowed++
+		}
+		if m.Failed() {
+			r.Totals.Failed++
+		}
+		if m.Baselined {
+			r.Totals.Baselined++
+		}
+		decisions[m.DecisionText]++
+
+		names := m.licenseNames()
+		if len(names) == 0 {
+			names = []string{"none"}
+		}
+		for _, name := range names {
+			lt, found := licenses[name]
+			if !found {
+				lt = &LicenseTotals{Name: name}
+				licenses[name] = lt
+			}
+			lt.Modules++
+			if m.Allowed() {
+				lt.Allowed++
+			} else {
+				lt.NotAllowed++
+			}
+		}
+	}
+	for _, lt := range licenses {
+		r.Licenses = append(r.Licenses, *lt)
+	}
+	sort.Slice(r.Licenses, func(i, j int) bool {
+		if r.Licenses[i].Modules != r.Licenses[j].Modules {
+			return r.Licenses[i].Modules > r.Licenses[j].Modules
+		}
+		return r.Licenses[i].Name < r.Licenses[j].Name
+	})
+	for decision, count := range decisions {
+		r.Decisions = append(r.Decisions, DecisionTotals{Decision: decision, Modules: count})
+	}
+	sort.Slice(r.Decisions, func(i, j int) bool { return r.Decisions[i].Decision < r.Decisions[j].Decision })
+
+	for _, bin := range summary.Binaries {
+		b := Binary{BuildInfo: bin}
+		for _, m := range r.Modules {
+			if !m.IsUsedBy(bin.Path) {
+				continue
+			}
+			em, policy := forBinary(m.EvaluatedModule, bin.Path)
+			bm := BinaryModule{
+				Module:       m.ModuleReference,
+				Licenses:     m.LicenseNames(),
+				DecisionText: decisionText(em.Decision),
+				Explanation:  em.ExplainDecision(),
+				Policy:       policy,
+				Allowed:      em.Allowed(),
+			}
+			if !bm.Allowed {
+				b.NotAllowed++
+			}
+			b.Modules = append(b.Modules, bm)
+		}
+		r.Binaries = append(r.Binaries, b)
+	}
+	return r
+}
+
+// forBinary returns the module as evaluated for the binary at the supplied path, along with the name of the policy
+// applied to it. Policies scoped to binaries record the decision for each binary, which may differ from the module's.
+func forBinary(m scan.EvaluatedModule, binPath string) (scan.EvaluatedModule, string) {
+	for _, bd := range m.Binaries {
+		if bd.Binary == binPath {
+			m.Decision, m.NotPermitted, m.Severity = bd.Decision, bd.NotPermitted, bd.Severity
+			return m, bd.Policy
+		}
+	}
+	return m, ""
+}
+
+func decisionText(d scan.Decision) string {
+	text, _ := d.MarshalText()
+	return string(text)
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
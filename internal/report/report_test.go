@@ -0,0 +1,311 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/report"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+var (
+	ref      = model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}
+	binaries = []model.BuildInfo{{Path: "/bin/tool"}, {Path: "/bin/server"}}
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		totals   report.Totals
+		licenses []report.LicenseTotals
+		binaries map[string]report.BinaryModule // the module as listed for each binary using it
+	}{
+		{
+			name: "allowed",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "MIT", Path: "/xyz/LICENSE", Confidence: 1}}},
+				Decision: scan.DecisionAllowed,
+				UsedBy:   []string{"/bin/tool", "/bin/server"},
+			},
+			totals:   report.Totals{Modules: 1, Allowed: 1},
+			licenses: []report.LicenseTotals{{Name: "MIT", Modules: 1, Allowed: 1}},
+			binaries: map[string]report.BinaryModule{
+				"/bin/tool":   {Module: ref, Licenses: "MIT", DecisionText: "allowed", Explanation: "allowed", Allowed: true},
+				"/bin/server": {Module: ref, Licenses: "MIT", DecisionText: "allowed", Explanation: "allowed", Allowed: true},
+			},
+		},
+		{
+			name: "policies scoped to binaries",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: ref,
+					Licenses:        []model.License{{Name: "GPL-3.0", Path: "/xyz/LICENSE", Confidence: 1}, {Name: "MIT", Path: "/xyz/LICENSE", Confidence: 1}},
+				},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			totals: report.Totals{Modules: 1, NotAllowed: 1, Failed: 1},
+			licenses: []report.LicenseTotals{
+				{Name: "GPL-3.0", Modules: 1, NotAllowed: 1},
+				{Name: "MIT", Modules: 1, NotAllowed: 1},
+			},
+			binaries: map[string]report.BinaryModule{
+				"/bin/tool": {Module: ref, Licenses: "GPL-3.0, MIT", DecisionText: "allowed", Explanation: "allowed", Policy: "tools", Allowed: true},
+				"/bin/server": {
+					Module:       ref,
+					Licenses:     "GPL-3.0, MIT",
+					DecisionText: "licenses-not-allowed",
+					Explanation:  "not allowed - non-permitted licenses: [GPL-3.0]",
+				},
+			},
+		},
+		{
+			name: "policies scoped to binaries, with different decisions",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref},
+				Decision: scan.DecisionNotAllowedModuleDenied,
+				Severity: scan.SeverityError,
+				Rules:    []scan.Rule{{Type: scan.RuleTypeModuleDeny, Policy: "tools", Path: "github.com/abc/*"}},
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionNotAllowedModuleDenied, Severity: scan.SeverityError},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedNoLicenseFiles, Severity: scan.SeverityWarn},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			totals:   report.Totals{Modules: 1, NotAllowed: 1, Failed: 1},
+			licenses: []report.LicenseTotals{{Name: "none", Modules: 1, NotAllowed: 1}},
+			binaries: map[string]report.BinaryModule{
+				"/bin/tool": {
+					Module:       ref,
+					Licenses:     "none",
+					DecisionText: "module-denied",
+					Explanation:  "not allowed - module denied by rule for github.com/abc/*",
+					Policy:       "tools",
+				},
+				"/bin/server": {
+					Module:       ref,
+					Licenses:     "none",
+					DecisionText: "no-license-files",
+					Explanation:  "not allowed - no license files found",
+				},
+			},
+		},
+		{
+			name: "policies scoped to binaries, not permitting different licenses",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: ref,
+					Licenses:        []model.License{{Name: "GPL-3.0", Path: "/xyz/LICENSE", Confidence: 1}, {Name: "LGPL-3.0", Path: "/xyz/COPYING", Confidence: 1}},
+				},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0", "LGPL-3.0"},
+				Severity:     scan.SeverityError,
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"LGPL-3.0"}, Severity: scan.SeverityError},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			totals: report.Totals{Modules: 1, NotAllowed: 1, Failed: 1},
+			licenses: []report.LicenseTotals{
+				{Name: "GPL-3.0", Modules: 1, NotAllowed: 1},
+				{Name: "LGPL-3.0", Modules: 1, NotAllowed: 1},
+			},
+			binaries: map[string]report.BinaryModule{
+				"/bin/tool": {
+					Module:       ref,
+					Licenses:     "GPL-3.0, LGPL-3.0",
+					DecisionText: "licenses-not-allowed",
+					Explanation:  "not allowed - non-permitted licenses: [GPL-3.0]",
+					Policy:       "tools",
+				},
+				"/bin/server": {
+					Module:       ref,
+					Licenses:     "GPL-3.0, LGPL-3.0",
+					DecisionText: "licenses-not-allowed",
+					Explanation:  "not allowed - non-permitted licenses: [LGPL-3.0]",
+				},
+			},
+		},
+		{
+			name: "baselined",
+			module: scan.EvaluatedModule{
+				Module:    model.Module{ModuleReference: ref},
+				Decision:  scan.DecisionNotAllowedNoLicenseFiles,
+				Severity:  scan.SeverityError,
+				Baselined: true,
+				UsedBy:    []string{"/bin/server"},
+			},
+			totals:   report.Totals{Modules: 1, NotAllowed: 1, Baselined: 1},
+			licenses: []report.LicenseTotals{{Name: "none", Modules: 1, NotAllowed: 1}},
+			binaries: map[string]report.BinaryModule{
+				"/bin/server": {Module: ref, Licenses: "none", DecisionText: "no-license-files", Explanation: "not allowed - no license files found"},
+			},
+		},
+		{
+			name: "licenses set by override",
+			module: scan.EvaluatedModule{
+				Module:     model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "Acme Commercial", Confidence: 1}}},
+				Decision:   scan.DecisionAllowed,
+				Rules:      []scan.Rule{{Type: scan.RuleTypeOverride, Path: "github.com/abc/xyz", Licenses: []string{"Acme Commercial"}}},
+				Overridden: []model.License{{Name: "MIT", Path: "/xyz/LICENSE", Confidence: 0.5}},
+				UsedBy:     []string{"/bin/tool"},
+			},
+			totals:   report.Totals{Modules: 1, Allowed: 1},
+			licenses: []report.LicenseTotals{{Name: "Acme Commercial", Modules: 1, Allowed: 1}},
+			binaries: map[string]report.BinaryModule{
+				"/bin/tool": {Module: ref, Licenses: "Acme Commercial", DecisionText: "allowed", Explanation: "allowed", Allowed: true},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := report.New(scan.Summary{Binaries: binaries, Modules: []scan.EvaluatedModule{tc.module}}, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+
+			assert.Equal(t, "2023-06-01T12:00:00Z", r.Created)
+			assert.Equal(t, tc.totals, r.Totals)
+			assert.Equal(t, tc.licenses, r.Licenses)
+			assert.Equal(t, []report.DecisionTotals{{Decision: r.Modules[0].DecisionText, Modules: 1}}, r.Decisions)
+			require.Len(t, r.Binaries, len(binaries))
+			for _, b := range r.Binaries {
+				expected, found := tc.binaries[b.Path]
+				if !found {
+					assert.Empty(t, b.Modules, "binary %s", b.Path)
+					continue
+				}
+				assert.Equal(t, []report.BinaryModule{expected}, b.Modules, "binary %s", b.Path)
+				if expected.Allowed {
+					assert.Equal(t, 0, b.NotAllowed)
+				} else {
+					assert.Equal(t, 1, b.NotAllowed)
+				}
+			}
+		})
+	}
+}
+
+func TestModule_Search(t *testing.T) {
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		expected []string // terms that match
+		excluded []string // terms that don't
+	}{
+		{
+			name: "path, version and licenses",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: ref,
+					Licenses:        []model.License{{Name: "MIT", Path: "/xyz/LICENSE", Content: "Permission is hereby granted, unlike the GPL", Confidence: 1}},
+				},
+			},
+			expected: []string{"github.com/abc", "v1.0.0", "mit"},
+			excluded: []string{"gpl", "permission", "/xyz/license"},
+		},
+		{
+			name: "rules",
+			module: scan.EvaluatedModule{
+				Module: model.Module{ModuleReference: ref},
+				Rules: []scan.Rule{{
+					Type:     scan.RuleTypeOverride,
+					Policy:   "tools",
+					Path:     "github.com/abc/*",
+					Licenses: []string{"BSD-3-Clause"},
+					Metadata: scan.Metadata{Justification: "Licensed in README"},
+				}},
+			},
+			expected: []string{"override", "github.com/abc/*", "bsd-3-clause", "tools", "licensed in readme"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			search := report.Module{EvaluatedModule: tc.module}.Search()
+			for _, term := range tc.expected {
+				assert.Contains(t, search, term)
+			}
+			for _, term := range tc.excluded {
+				assert.NotContains(t, search, term)
+			}
+		})
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		expected []string // fragments of the HTML
+	}{
+		{
+			name: "license texts are escaped",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "MIT", Path: "/xyz/LICENSE", Content: "MIT <License>", Confidence: 1}}},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: []string{
+				`<tr data-decision="allowed" data-allowed="true" data-search="github.com/abc/xyz v1.0.0 mit">`,
+				`<pre>MIT &lt;License&gt;</pre>`,
+			},
+		},
+		{
+			name: "baselined, with rule metadata",
+			module: scan.EvaluatedModule{
+				Module:    model.Module{ModuleReference: ref},
+				Decision:  scan.DecisionNotAllowedNoLicenseFiles,
+				Severity:  scan.SeverityError,
+				Baselined: true,
+				Rules: []scan.Rule{
+					{Type: scan.RuleTypeNoLicenseFiles, Path: "github.com/abc/xyz", Metadata: scan.Metadata{Justification: "vendored from <upstream>"}},
+				},
+			},
+			expected: []string{
+				`data-decision="no-license-files" data-allowed="false"`,
+				`<li>noLicenseFiles github.com/abc/xyz<br>vendored from &lt;upstream&gt;</li>`,
+				`not allowed - no license files found (baselined)`,
+			},
+		},
+		{
+			name: "licenses set by override",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "BSD-3-Clause", Confidence: 1}}},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: []string{`<p><strong>BSD-3-Clause</strong> (confidence 1.00)<br>set by override</p>`},
+		},
+		{
+			name: "main module",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: model.ModuleReference{Path: "github.com/acme/app", Version: "(devel)"}, Main: true},
+				Decision: scan.DecisionNotAllowedModuleUnavailable,
+			},
+			expected: []string{`<td>github.com/acme/app (main)</td>`},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, report.WriteHTML(&buf, report.New(scan.Summary{Modules: []scan.EvaluatedModule{tc.module}}, time.Now())))
+
+			html := buf.String()
+			for _, fragment := range tc.expected {
+				assert.Contains(t, html, fragment)
+			}
+			assert.NotContains(t, html, `<link`, "the report must not depend on external resources")
+		})
+	}
+}
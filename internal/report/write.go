@@ -0,0 +1,20 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+)
+
+//go:embed report.html
+var reportHTML string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(reportHTML))
+
+// WriteHTML writes the report as a single HTML file, with no external dependencies so that it can be viewed offline
+func WriteHTML(w io.Writer, r Report) error {
+	return reportTemplate.Execute(w, r)
+}
@@ -0,0 +1,316 @@
+package results
+
+import (
+	"fmt"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// Decisions are the values of the Decision fields
+var Decisions = []string{
+	"allowed",
+	"module-approved",
+	"licenses-not-allowed",
+	"module-denied",
+	"no-license-files",
+	"unclassified-license",
+	"module-unavailable",
+	"unresolvable-license",
+}
+
+// Severities are the values of the Severity fields
+var Severities = []string{"info", "warn", "error"}
+
+// RuleTypes are the values of the Type field of rules
+var RuleTypes = []string{
+	"override",
+	"licenseNotPermitted",
+	"unresolvableLicense",
+	"noLicenseFiles",
+	"unclassifiedLicense",
+	"moduleUnavailable",
+	"moduleAllow",
+	"moduleDeny",
+}
+
+// FromSummary returns the results of the scan, in the current version of the schema
+func FromSummary(summary scan.Summary) (Results, error) {
+	r := Results{
+		SchemaVersion: SchemaVersion,
+		Modules:       make([]Module, 0, len(summary.Modules)),
+		Binaries:      make([]Binary, 0, len(summary.Binaries)),
+	}
+	for _, em := range summary.Modules {
+		m, err := fromModule(em)
+		if err != nil {
+			return Results{}, fmt.Errorf("module %s: %w", em.ModuleReference, err)
+		}
+		r.Modules = append(r.Modules, m)
+	}
+	for _, bin := range summary.Binaries {
+		r.Binaries = append(r.Binaries, Binary{
+			Path:          bin.Path,
+			PackagePath:   bin.PackagePath,
+			ModulePath:    bin.ModulePath,
+			ModuleVersion: bin.ModuleVersion,
+			ModuleRefs:    fromModuleRefs(bin.ModuleRefs),
+		})
+	}
+	for _, sr := range summary.StaleRules {
+		r.StaleRules = append(r.StaleRules, StaleRule{
+			Rule:     fromRule(sr.Rule),
+			Reason:   sr.Reason,
+			Severity: sr.Severity.String(),
+		})
+	}
+	for _, c := range summary.Comparisons {
+		r.Comparisons = append(r.Comparisons, LicenseComparison{
+			SBOM:     c.SBOM,
+			Module:   ModuleReference{Path: c.Module.Path, Version: c.Module.Version},
+			Declared: nonNil(c.Declared),
+			Detected: nonNil(c.Detected),
+		})
+	}
+	return r, nil
+}
+
+func fromModule(em scan.EvaluatedModule) (Module, error) {
+	decision, err := em.Decision.MarshalText()
+	if err != nil {
+		return Module{}, err
+	}
+	m := Module{
+		Path:           em.Path,
+		Version:        em.Version,
+		Dir:            em.Dir,
+		Unavailable:    em.Unavailable,
+		Sum:            em.Sum,
+		ZipSHA256:      em.ZipSHA256,
+		Licenses:       fromLicenses(em.Licenses),
+		BelowThreshold: fromLicenses(em.BelowThreshold),
+		LicenseFiles:   em.LicenseFiles,
+		Main:           em.Main,
+		Decision:       string(decision),
+		NotPermitted:   em.NotPermitted,
+		Severity:       em.Severity.String(),
+		UsedBy:         nonNil(em.UsedBy),
+		Baselined:      em.Baselined,
+	}
+	if len(m.BelowThreshold) == 0 {
+		m.BelowThreshold = nil
+	}
+	if len(em.Overridden) > 0 {
+		m.Overridden = fromLicenses(em.Overridden)
+	}
+	for _, nf := range em.NoticeFiles {
+		m.NoticeFiles = append(m.NoticeFiles, NoticeFile{Path: nf.Path, Content: nf.Content})
+	}
+	for _, n := range em.Notices {
+		m.Notices = append(m.Notices, Notice{Severity: n.Severity.String(), Message: n.Message})
+	}
+	for _, rule := range em.Rules {
+		m.Rules = append(m.Rules, fromRule(rule))
+	}
+	for _, bd := range em.Binaries {
+		decision, err := bd.Decision.MarshalText()
+		if err != nil {
+			return Module{}, err
+		}
+		m.Binaries = append(m.Binaries, BinaryDecision{
+			Binary:       bd.Binary,
+			Policy:       bd.Policy,
+			Decision:     string(decision),
+			NotPermitted: bd.NotPermitted,
+			Severity:     bd.Severity.String(),
+		})
+	}
+	return m, nil
+}
+
+func fromLicenses(licenses []model.License) []License {
+	out := make([]License, 0, len(licenses))
+	for _, lic := range licenses {
+		out = append(out, License{Path: lic.Path, Content: lic.Content, Name: lic.Name, Confidence: lic.Confidence})
+	}
+	return out
+}
+
+func fromModuleRefs(refs []model.ModuleReference) []ModuleReference {
+	out := make([]ModuleReference, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, ModuleReference{Path: ref.Path, Version: ref.Version})
+	}
+	return out
+}
+
+func fromRule(rule scan.Rule) Rule {
+	r := Rule{
+		Type:          string(rule.Type),
+		Policy:        rule.Policy,
+		Path:          rule.Path,
+		Version:       rule.Version,
+		Licenses:      rule.Licenses,
+		Justification: rule.Justification,
+		Owner:         rule.Owner,
+		Reference:     rule.Reference,
+	}
+	if rule.Expires != nil {
+		r.Expires = rule.Expires.String()
+	}
+	return r
+}
+
+// nonNil returns the list, or an empty list if it is nil, so that required lists are never written as null
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// Summary returns the scan summary the results were written from
+func (r Results) Summary() (scan.Summary, error) {
+	var summary scan.Summary
+	for _, m := range r.Modules {
+		em, err := m.evaluatedModule()
+		if err != nil {
+			return scan.Summary{}, fmt.Errorf("module %s@%s: %w", m.Path, m.Version, err)
+		}
+		summary.Modules = append(summary.Modules, em)
+	}
+	for _, bin := range r.Binaries {
+		summary.Binaries = append(summary.Binaries, model.BuildInfo{
+			Path:          bin.Path,
+			PackagePath:   bin.PackagePath,
+			ModulePath:    bin.ModulePath,
+			ModuleVersion: bin.ModuleVersion,
+			ModuleRefs:    toModuleRefs(bin.ModuleRefs),
+		})
+	}
+	for _, sr := range r.StaleRules {
+		rule, err := sr.Rule.rule()
+		if err != nil {
+			return scan.Summary{}, fmt.Errorf("stale rule for %s: %w", sr.Path, err)
+		}
+		severity, err := parseSeverity(sr.Severity)
+		if err != nil {
+			return scan.Summary{}, fmt.Errorf("stale rule for %s: %w", sr.Path, err)
+		}
+		summary.StaleRules = append(summary.StaleRules, scan.StaleRule{Rule: rule, Reason: sr.Reason, Severity: severity})
+	}
+	for _, c := range r.Comparisons {
+		summary.Comparisons = append(summary.Comparisons, scan.LicenseComparison{
+			SBOM:     c.SBOM,
+			Module:   model.ModuleReference{Path: c.Module.Path, Version: c.Module.Version},
+			Declared: c.Declared,
+			Detected: c.Detected,
+		})
+	}
+	return summary, nil
+}
+
+func (m Module) evaluatedModule() (scan.EvaluatedModule, error) {
+	em := scan.EvaluatedModule{
+		Module: model.Module{
+			ModuleReference: model.ModuleReference{Path: m.Path, Version: m.Version},
+			Dir:             m.Dir,
+			Unavailable:     m.Unavailable,
+			Sum:             m.Sum,
+			ZipSHA256:       m.ZipSHA256,
+			Licenses:        toLicenses(m.Licenses),
+			BelowThreshold:  toLicenses(m.BelowThreshold),
+			LicenseFiles:    m.LicenseFiles,
+			Main:            m.Main,
+		},
+		NotPermitted: m.NotPermitted,
+		Overridden:   toLicenses(m.Overridden),
+		UsedBy:       m.UsedBy,
+		Baselined:    m.Baselined,
+	}
+	if err := em.Decision.UnmarshalText([]byte(m.Decision)); err != nil {
+		return scan.EvaluatedModule{}, err
+	}
+	var err error
+	if em.Severity, err = parseSeverity(m.Severity); err != nil {
+		return scan.EvaluatedModule{}, err
+	}
+	for _, nf := range m.NoticeFiles {
+		em.NoticeFiles = append(em.NoticeFiles, model.NoticeFile{Path: nf.Path, Content: nf.Content})
+	}
+	for _, n := range m.Notices {
+		severity, err := parseSeverity(n.Severity)
+		if err != nil {
+			return scan.EvaluatedModule{}, err
+		}
+		em.Notices = append(em.Notices, scan.Notice{Severity: severity, Message: n.Message})
+	}
+	for _, r := range m.Rules {
+		rule, err := r.rule()
+		if err != nil {
+			return scan.EvaluatedModule{}, err
+		}
+		em.Rules = append(em.Rules, rule)
+	}
+	for _, b := range m.Binaries {
+		bd := scan.BinaryDecision{Binary: b.Binary, Policy: b.Policy, NotPermitted: b.NotPermitted}
+		if err := bd.Decision.UnmarshalText([]byte(b.Decision)); err != nil {
+			return scan.EvaluatedModule{}, err
+		}
+		if bd.Severity, err = parseSeverity(b.Severity); err != nil {
+			return scan.EvaluatedModule{}, err
+		}
+		em.Binaries = append(em.Binaries, bd)
+	}
+	return em, nil
+}
+
+func toLicenses(licenses []License) []model.License {
+	if len(licenses) == 0 {
+		return nil
+	}
+	out := make([]model.License, 0, len(licenses))
+	for _, lic := range licenses {
+		out = append(out, model.License{Path: lic.Path, Content: lic.Content, Name: lic.Name, Confidence: lic.Confidence})
+	}
+	return out
+}
+
+func toModuleRefs(refs []ModuleReference) []model.ModuleReference {
+	out := make([]model.ModuleReference, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, model.ModuleReference{Path: ref.Path, Version: ref.Version})
+	}
+	return out
+}
+
+func (r Rule) rule() (scan.Rule, error) {
+	rule := scan.Rule{
+		Type:     scan.RuleType(r.Type),
+		Policy:   r.Policy,
+		Path:     r.Path,
+		Version:  r.Version,
+		Licenses: r.Licenses,
+		Metadata: scan.Metadata{
+			Justification: r.Justification,
+			Owner:         r.Owner,
+			Reference:     r.Reference,
+		},
+	}
+	if r.Expires != "" {
+		rule.Expires = &scan.Date{}
+		if err := rule.Expires.UnmarshalText([]byte(r.Expires)); err != nil {
+			return scan.Rule{}, err
+		}
+	}
+	return rule, nil
+}
+
+// parseSeverity parses a severity, which is empty if not set
+func parseSeverity(s string) (severity scan.Severity, err error) {
+	if s == "" {
+		return 0, nil
+	}
+	err = severity.UnmarshalText([]byte(s))
+	return severity, err
+}
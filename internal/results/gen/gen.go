@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/uw-labs/lichen/internal/results"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	b, err := results.Schema()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(os.Args[1], b, 0644)
+}
@@ -0,0 +1,34 @@
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Write writes the results as JSON
+func Write(w io.Writer, r Results) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// Read reads results written as JSON. Results written before the schema was versioned have no schemaVersion, and are
+// read as version 1.0, which they are compatible with. Results written by later minor versions are read, ignoring any
+// fields added since, while those written by other major versions are rejected.
+func Read(r io.Reader) (Results, error) {
+	var results Results
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		return Results{}, fmt.Errorf("failed to decode json: %w", err)
+	}
+	if results.SchemaVersion == "" {
+		results.SchemaVersion = SchemaVersion
+	}
+	if major(results.SchemaVersion) != major(SchemaVersion) {
+		return Results{}, fmt.Errorf("unsupported schema version %q (expected %s.x)", results.SchemaVersion, major(SchemaVersion))
+	}
+	return results, nil
+}
+
+func major(version string) string {
+	return strings.SplitN(version, ".", 2)[0]
+}
@@ -0,0 +1,118 @@
+package results
+
+//go:generate go run ./gen/ ../../schema/results.schema.json
+
+// SchemaVersion is the version of the JSON results schema, of the form major.minor. The minor version is incremented
+// when fields are added, while the major version is only incremented by changes that could break existing readers,
+// such as removing, renaming or changing the type of a field.
+const SchemaVersion = "1.0"
+
+// The types below define the JSON results written by lichen. They are deliberately decoupled from the types used
+// internally, so that internal changes do not change the results; any change to them must follow the versioning
+// described above, and be reflected in the generated JSON Schema. Field names match those written before the schema
+// was versioned, so that existing readers continue to work.
+
+// Results are the results of a scan
+type Results struct {
+	SchemaVersion string              `json:"schemaVersion" description:"version of the results schema (major.minor)"`
+	Modules       []Module            `json:"Modules" description:"evaluated modules, sorted by path"`
+	Binaries      []Binary            `json:"Binaries" description:"scanned binaries, and SBOMs scanned in place of binaries"`
+	StaleRules    []StaleRule         `json:"StaleRules,omitempty" description:"overrides and exceptions that did not apply to any module"`
+	Comparisons   []LicenseComparison `json:"Comparisons,omitempty" description:"licenses declared by scanned SBOMs, compared with those detected"`
+}
+
+// Module is an evaluated module
+type Module struct {
+	Path           string           `json:"Path" description:"module path"`
+	Version        string           `json:"Version" description:"module version"`
+	Dir            string           `json:"Dir" description:"absolute path to the module's source, empty if unavailable"`
+	Unavailable    string           `json:"Unavailable,omitempty" description:"reason the module's source is unavailable, if it is"`
+	Sum            string           `json:"Sum,omitempty" description:"checksum of the module, as recorded in go.sum (h1:...)"`
+	ZipSHA256      string           `json:"ZipSHA256,omitempty" description:"hex encoded SHA-256 hash of the module's zip, as downloaded from the module proxy"`
+	Licenses       []License        `json:"Licenses" description:"resolved licenses, including those set by overrides"`
+	BelowThreshold []License        `json:"BelowThreshold,omitempty" description:"license matches below the confidence threshold, which are not resolved"`
+	LicenseFiles   []string         `json:"LicenseFiles,omitempty" description:"absolute paths to the license files found in the module"`
+	NoticeFiles    []NoticeFile     `json:"NoticeFiles,omitempty" description:"NOTICE files found in the module"`
+	Main           bool             `json:"Main,omitempty" description:"true if the module is the main module of a scanned binary"`
+	Decision       string           `json:"Decision" enum:"decisions" description:"outcome of evaluating the module"`
+	NotPermitted   []string         `json:"NotPermitted,omitempty" description:"licenses of the module that are not permitted"`
+	Severity       string           `json:"Severity,omitempty" enum:"severities" description:"highest severity of the decision and notices"`
+	Notices        []Notice         `json:"Notices,omitempty" description:"observations made during evaluation that do not alter the decision"`
+	Rules          []Rule           `json:"Rules,omitempty" description:"overrides and exceptions applied to the module"`
+	Overridden     []License        `json:"Overridden,omitempty" description:"licenses detected in the module's license files, replaced by an override"`
+	Binaries       []BinaryDecision `json:"Binaries,omitempty" description:"decisions for each binary, when policies are scoped to binaries"`
+	UsedBy         []string         `json:"UsedBy" description:"paths of the binaries using the module"`
+	Baselined      bool             `json:"Baselined,omitempty" description:"true if the module's failure is accepted by the baseline"`
+}
+
+// License is a license of a module
+type License struct {
+	Path       string  `json:"Path" description:"absolute path to the license file, empty if set by an override"`
+	Content    string  `json:"Content" description:"contents of the license file"`
+	Name       string  `json:"Name" description:"SPDX identifier of the license"`
+	Confidence float64 `json:"Confidence" description:"confidence of the license classification, from 0 to 1"`
+}
+
+// NoticeFile is a NOTICE file distributed with a module
+type NoticeFile struct {
+	Path    string `json:"Path" description:"absolute path to the NOTICE file"`
+	Content string `json:"Content" description:"contents of the NOTICE file"`
+}
+
+// Notice is an observation made while evaluating a module
+type Notice struct {
+	Severity string `json:"Severity" enum:"severities" description:"severity of the notice"`
+	Message  string `json:"Message" description:"description of the notice"`
+}
+
+// Rule is an override or exception
+type Rule struct {
+	Type          string   `json:"Type" enum:"ruleTypes" description:"type of rule"`
+	Policy        string   `json:"Policy,omitempty" description:"name of the policy defining the rule, empty for the top-level config"`
+	Path          string   `json:"Path" description:"module path the rule matches"`
+	Version       string   `json:"Version,omitempty" description:"module version range the rule matches"`
+	Licenses      []string `json:"Licenses,omitempty" description:"licenses the rule sets or permits"`
+	Justification string   `json:"Justification,omitempty" description:"reason for the rule"`
+	Owner         string   `json:"Owner,omitempty" description:"owner of the rule"`
+	Reference     string   `json:"Reference,omitempty" description:"link to a ticket, agreement or similar"`
+	Expires       string   `json:"Expires,omitempty" format:"date" description:"date after which the rule no longer applies (YYYY-MM-DD)"`
+}
+
+// BinaryDecision is the outcome of evaluating a module against the policy of a binary that uses it
+type BinaryDecision struct {
+	Binary       string   `json:"Binary" description:"path of the binary"`
+	Policy       string   `json:"Policy,omitempty" description:"name of the scoped policy applied, empty for the top-level config"`
+	Decision     string   `json:"Decision" enum:"decisions" description:"outcome of evaluating the module for the binary"`
+	NotPermitted []string `json:"NotPermitted,omitempty" description:"licenses of the module that are not permitted for the binary"`
+	Severity     string   `json:"Severity,omitempty" enum:"severities" description:"severity of the decision"`
+}
+
+// Binary is a scanned binary, or an SBOM scanned in place of a binary
+type Binary struct {
+	Path          string            `json:"Path" description:"absolute path to the binary or SBOM"`
+	PackagePath   string            `json:"PackagePath" description:"path of the main package of the binary"`
+	ModulePath    string            `json:"ModulePath" description:"path of the main module of the binary"`
+	ModuleVersion string            `json:"ModuleVersion" description:"version of the main module, (devel) if built from a source checkout"`
+	ModuleRefs    []ModuleReference `json:"ModuleRefs" description:"modules used by the binary"`
+}
+
+// ModuleReference is a reference to a particular version of a module
+type ModuleReference struct {
+	Path    string `json:"Path" description:"module path"`
+	Version string `json:"Version" description:"module version"`
+}
+
+// StaleRule is an override or exception that did not apply to any module
+type StaleRule struct {
+	Rule
+	Reason   string `json:"Reason" description:"why the rule is stale"`
+	Severity string `json:"Severity" enum:"severities" description:"severity of the rule being stale"`
+}
+
+// LicenseComparison compares the licenses declared for a module by an SBOM with those detected
+type LicenseComparison struct {
+	SBOM     string          `json:"SBOM" description:"absolute path to the SBOM"`
+	Module   ModuleReference `json:"Module" description:"the module the licenses were declared for"`
+	Declared []string        `json:"Declared" description:"licenses declared by the SBOM"`
+	Detected []string        `json:"Detected" description:"licenses detected in the module's license files, prior to any overrides"`
+}
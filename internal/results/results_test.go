@@ -0,0 +1,208 @@
+package results_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/results"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var expires scan.Date
+	require.NoError(t, expires.UnmarshalText([]byte("2030-01-31")))
+	rule := scan.Rule{
+		Type:     scan.RuleTypeOverride,
+		Policy:   "tools",
+		Path:     "github.com/abc/xyz",
+		Version:  "<v2.0.0",
+		Licenses: []string{"MIT"},
+		Metadata: scan.Metadata{Justification: "licensed in README", Owner: "team", Expires: &expires},
+	}
+	summary := scan.Summary{
+		Modules: []scan.EvaluatedModule{
+			{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"},
+					Dir:             "/mod/xyz",
+					Sum:             "h1:abc=",
+					ZipSHA256:       "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+					Licenses:        []model.License{{Name: "MIT", Path: "/mod/xyz/LICENSE", Content: "text", Confidence: 0.98}},
+					BelowThreshold:  []model.License{{Name: "BSD-3-Clause", Path: "/mod/xyz/COPYING", Confidence: 0.5}},
+					LicenseFiles:    []string{"/mod/xyz/LICENSE", "/mod/xyz/COPYING"},
+					NoticeFiles:     []model.NoticeFile{{Path: "/mod/xyz/NOTICE", Content: "notice"}},
+				},
+				Decision: scan.DecisionAllowed,
+				Severity: scan.SeverityWarn,
+				Notices:  []scan.Notice{{Severity: scan.SeverityWarn, Message: "override expires soon"}},
+				Rules:    []scan.Rule{rule},
+				Overridden: []model.License{
+					{Name: "Apache-2.0", Path: "/mod/xyz/LICENSE.apache", Content: "Apache License", Confidence: 0.95},
+				},
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/foo", Policy: "tools", Decision: scan.DecisionAllowed},
+					{Binary: "/bin/bar", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"MIT"}, Severity: scan.SeverityError},
+				},
+				UsedBy:    []string{"/bin/foo", "/bin/bar"},
+				Baselined: true,
+			},
+			{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/foo/bar", Version: "(devel)"},
+					Unavailable:     "built from a source checkout, and no source directory was supplied via --source",
+					Main:            true,
+				},
+				Decision: scan.DecisionNotAllowedNoLicenseFiles,
+				Severity: scan.SeverityError,
+				UsedBy:   []string{"/bin/foo"},
+			},
+		},
+		Binaries: []model.BuildInfo{
+			{
+				Path:          "/bin/foo",
+				PackagePath:   "github.com/foo/bar/cmd/foo",
+				ModulePath:    "github.com/foo/bar",
+				ModuleVersion: "(devel)",
+				ModuleRefs:    []model.ModuleReference{{Path: "github.com/abc/xyz", Version: "v1.0.0"}},
+			},
+		},
+		StaleRules: []scan.StaleRule{{Rule: rule, Reason: "matched no modules", Severity: scan.SeverityInfo}},
+		Comparisons: []scan.LicenseComparison{
+			{
+				SBOM:     "/sbom.json",
+				Module:   model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"},
+				Declared: []string{"Apache-2.0"},
+				Detected: []string{"MIT"},
+			},
+		},
+	}
+
+	r, err := results.FromSummary(summary)
+	require.NoError(t, err)
+	assert.Equal(t, results.SchemaVersion, r.SchemaVersion)
+
+	var buf bytes.Buffer
+	require.NoError(t, results.Write(&buf, r))
+	read, err := results.Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, r, read)
+
+	actual, err := read.Summary()
+	require.NoError(t, err)
+	assert.Equal(t, summary, actual)
+}
+
+func TestFromSummaryRequiredLists(t *testing.T) {
+	r, err := results.FromSummary(scan.Summary{
+		Modules: []scan.EvaluatedModule{{Decision: scan.DecisionNotAllowedModuleUnavailable}},
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"schemaVersion": "1.0",
+		"Modules": [{"Path": "", "Version": "", "Dir": "", "Licenses": [], "Decision": "module-unavailable", "UsedBy": []}],
+		"Binaries": []
+	}`, string(b))
+}
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected results.Results
+		err      string
+	}{
+		{
+			name:  "unversioned",
+			input: `{"Modules":[{"Path":"github.com/abc/xyz","Version":"v1.0.0","Dir":"","Licenses":null,"Decision":"allowed","UsedBy":null}],"Binaries":null}`,
+			expected: results.Results{
+				SchemaVersion: "1.0",
+				Modules:       []results.Module{{Path: "github.com/abc/xyz", Version: "v1.0.0", Decision: "allowed"}},
+			},
+		},
+		{
+			name:  "later minor version",
+			input: `{"schemaVersion":"1.7","Modules":[],"Binaries":[],"Added":true}`,
+			expected: results.Results{
+				SchemaVersion: "1.7",
+				Modules:       []results.Module{},
+				Binaries:      []results.Binary{},
+			},
+		},
+		{
+			name:  "later major version",
+			input: `{"schemaVersion":"2.0","Modules":[],"Binaries":[]}`,
+			err:   `unsupported schema version "2.0" (expected 1.x)`,
+		},
+		{
+			name:  "invalid json",
+			input: `{"Modules":`,
+			err:   "failed to decode json",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := results.Read(strings.NewReader(tc.input))
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, r)
+		})
+	}
+}
+
+func TestSummaryInvalid(t *testing.T) {
+	_, err := results.Results{Modules: []results.Module{{Path: "github.com/abc/xyz", Version: "v1.0.0", Decision: "maybe"}}}.Summary()
+	require.Error(t, err)
+	assert.Equal(t, `module github.com/abc/xyz@v1.0.0: unrecognised decision "maybe"`, err.Error())
+}
+
+// TestEnums checks that every value written by the scan is listed by the schema
+func TestEnums(t *testing.T) {
+	for d := scan.DecisionAllowed; d <= scan.DecisionNotAllowedModuleUnavailable; d++ {
+		text, err := d.MarshalText()
+		require.NoError(t, err)
+		assert.Contains(t, results.Decisions, string(text))
+	}
+	assert.Len(t, results.Decisions, int(scan.DecisionNotAllowedModuleUnavailable))
+
+	for _, s := range []scan.Severity{scan.SeverityInfo, scan.SeverityWarn, scan.SeverityError} {
+		assert.Contains(t, results.Severities, s.String())
+	}
+
+	for _, rt := range []scan.RuleType{
+		scan.RuleTypeOverride,
+		scan.RuleTypeLicenseNotPermitted,
+		scan.RuleTypeUnresolvableLicense,
+		scan.RuleTypeNoLicenseFiles,
+		scan.RuleTypeUnclassifiedLicense,
+		scan.RuleTypeModuleUnavailable,
+		scan.RuleTypeModuleAllow,
+		scan.RuleTypeModuleDeny,
+	} {
+		assert.Contains(t, results.RuleTypes, string(rt))
+	}
+}
+
+// TestSchema checks that the published schema has been regenerated (go generate ./internal/results/) since the types
+// last changed
+func TestSchema(t *testing.T) {
+	expected, err := results.Schema()
+	require.NoError(t, err)
+	actual, err := os.ReadFile("../../schema/results.schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+}
@@ -0,0 +1,172 @@
+package results
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Schema returns the JSON Schema describing results, as generated from the types in this package
+func Schema() ([]byte, error) {
+	g := schemaGenerator{defs: make(map[string]*schema)}
+	root, err := g.object(reflect.TypeOf(Results{}))
+	if err != nil {
+		return nil, err
+	}
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.Title = "lichen results"
+	root.Description = "Results of a lichen scan, as written by the json output format"
+	root.Defs = g.defs
+
+	b, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+type schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Items       *schema            `json:"items,omitempty"`
+	Properties  properties         `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Defs        map[string]*schema `json:"$defs,omitempty"`
+}
+
+// property is a property of an object schema
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties are the properties of an object schema, written in the order the fields are declared
+type properties []property
+
+func (p properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(prop.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(prop.schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// enums are the values of fields with an enum tag, keyed by the tag
+var enums = map[string][]string{
+	"decisions":  Decisions,
+	"severities": Severities,
+	"ruleTypes":  RuleTypes,
+}
+
+type schemaGenerator struct {
+	defs map[string]*schema // object schemas, keyed by type name
+}
+
+// object returns the schema of a struct, with fields of embedded structs promoted as they are by encoding/json
+func (g schemaGenerator) object(t reflect.Type) (*schema, error) {
+	s := &schema{Type: "object"}
+	if err := g.addFields(s, t); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (g schemaGenerator) addFields(s *schema, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			if err := g.addFields(s, f.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "" {
+			return fmt.Errorf("field %s.%s has no json name", t.Name(), f.Name)
+		}
+		fs, err := g.field(f)
+		if err != nil {
+			return fmt.Errorf("field %s.%s: %w", t.Name(), f.Name, err)
+		}
+		s.Properties = append(s.Properties, property{name: name, schema: fs})
+		if len(tag) < 2 || tag[1] != "omitempty" {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return nil
+}
+
+func (g schemaGenerator) field(f reflect.StructField) (*schema, error) {
+	s, err := g.typ(f.Type)
+	if err != nil {
+		return nil, err
+	}
+	s.Description = f.Tag.Get("description")
+	s.Format = f.Tag.Get("format")
+	if name := f.Tag.Get("enum"); name != "" {
+		values, found := enums[name]
+		if !found {
+			return nil, fmt.Errorf("unrecognised enum %q", name)
+		}
+		s.Enum = values
+	}
+	if f.Name == "SchemaVersion" {
+		s.Pattern = fmt.Sprintf(`^%s\.[0-9]+$`, major(SchemaVersion))
+	}
+	return s, nil
+}
+
+func (g schemaGenerator) typ(t reflect.Type) (*schema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &schema{Type: "boolean"}, nil
+	case reflect.Float64:
+		return &schema{Type: "number"}, nil
+	case reflect.Int:
+		return &schema{Type: "integer"}, nil
+	case reflect.Slice:
+		items, err := g.typ(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Type: "array", Items: items}, nil
+	case reflect.Struct:
+		if _, found := g.defs[t.Name()]; !found {
+			g.defs[t.Name()] = nil // reserve the name, in case of recursive types
+			def, err := g.object(t)
+			if err != nil {
+				return nil, err
+			}
+			g.defs[t.Name()] = def
+		}
+		return &schema{Ref: "#/$defs/" + t.Name()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
@@ -0,0 +1,248 @@
+package sarif
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/scan"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	version        = "2.1.0"
+	schema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	informationURI = "https://github.com/uw-labs/lichen"
+)
+
+// Log is a SARIF 2.1.0 log, with the subset of fields populated by lichen
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule describes a type of decision that does not allow a module
+type Rule struct {
+	ID               string  `json:"id"`
+	ShortDescription Message `json:"shortDescription"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+type Result struct {
+	RuleID       string        `json:"ruleId"`
+	RuleIndex    int           `json:"ruleIndex"`
+	Level        string        `json:"level"`
+	Message      Message       `json:"message"`
+	Locations    []Location    `json:"locations,omitempty"`
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type Region struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// Suppression records that a result has been accepted, e.g. by the baseline
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// rules covers every decision that does not allow a module, in the order they are listed in the log
+var rules = []struct {
+	decision    scan.Decision
+	description string
+}{
+	{scan.DecisionNotAllowedLicenseNotPermitted, "Module licenses are not permitted"},
+	{scan.DecisionNotAllowedModuleDenied, "Module is denied"},
+	{scan.DecisionNotAllowedNoLicenseFiles, "Module has no license files"},
+	{scan.DecisionNotAllowedUnclassifiedLicense, "Module license files could not be classified"},
+	{scan.DecisionNotAllowedModuleUnavailable, "Module source is unavailable"},
+	{scan.DecisionNotAllowedUnresolvableLicense, "Module licenses could not be resolved"},
+}
+
+// noticesRule covers modules that are allowed, but with notices severe enough to report (e.g. of an expired rule, which
+// can fail the run). It is listed after the rules for each decision.
+var noticesRule = Rule{ID: "notices", ShortDescription: Message{Text: "Module is allowed, with notices"}}
+
+// New returns a log with a result for each module that is not allowed, or that is allowed with notices of at least a
+// warning severity. If moduleRoot is supplied, results are located at the line of its go.mod that requires the module,
+// or otherwise at its module directive.
+func New(summary scan.Summary, moduleRoot string) (Log, error) {
+	driver := Driver{
+		Name:           "lichen",
+		InformationURI: informationURI,
+		Rules:          make([]Rule, 0, len(rules)),
+	}
+	ruleIndex := make(map[scan.Decision]int, len(rules))
+	for i, r := range rules {
+		id, err := r.decision.MarshalText()
+		if err != nil {
+			return Log{}, err
+		}
+		ruleIndex[r.decision] = i
+		driver.Rules = append(driver.Rules, Rule{ID: string(id), ShortDescription: Message{Text: r.description}})
+	}
+	noticesIndex := len(driver.Rules)
+	driver.Rules = append(driver.Rules, noticesRule)
+
+	var gomod *goMod
+	if moduleRoot != "" {
+		var err error
+		if gomod, err = readGoMod(moduleRoot); err != nil {
+			return Log{}, err
+		}
+	}
+
+	results := make([]Result, 0)
+	for _, m := range summary.Modules {
+		if m.Allowed() && m.Severity < scan.SeverityWarn {
+			continue
+		}
+		i := noticesIndex
+		if !m.Allowed() {
+			var found bool
+			if i, found = ruleIndex[m.Decision]; !found {
+				return Log{}, fmt.Errorf("no rule for decision of %s", m.ModuleReference)
+			}
+		}
+		res := Result{
+			RuleID:    driver.Rules[i].ID,
+			RuleIndex: i,
+			Level:     level(m.Severity),
+			Message:   Message{Text: fmt.Sprintf("%s: %s", m.ModuleReference, explain(m))},
+		}
+		if gomod != nil {
+			res.Locations = []Location{gomod.locate(m.Path)}
+		}
+		if m.Baselined {
+			res.Suppressions = []Suppression{{Kind: "external", Justification: "accepted by baseline"}}
+		}
+		results = append(results, res)
+	}
+
+	return Log{
+		Schema:  schema,
+		Version: version,
+		Runs: []Run{{
+			Tool:    Tool{Driver: driver},
+			Results: results,
+		}},
+	}, nil
+}
+
+// explain describes the decision of the module, along with any notices of at least a warning severity
+func explain(m scan.EvaluatedModule) string {
+	explanation := m.ExplainDecision()
+	for _, n := range m.Notices {
+		if n.Severity >= scan.SeverityWarn {
+			explanation += "; " + n.Message
+		}
+	}
+	return explanation
+}
+
+// level returns the SARIF level equivalent of the severity. Modules that are not allowed always have a severity, but
+// are treated as errors if not.
+func level(s scan.Severity) string {
+	switch s {
+	case scan.SeverityInfo:
+		return "note"
+	case scan.SeverityWarn:
+		return "warning"
+	default:
+		return "error"
+	}
+}
+
+// goMod is a parsed go.mod, along with the URI results are located at
+type goMod struct {
+	uri  string
+	file *modfile.File
+}
+
+func readGoMod(moduleRoot string) (*goMod, error) {
+	p := filepath.Join(moduleRoot, "go.mod")
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod of module root: %w", err)
+	}
+	f, err := modfile.ParseLax(p, b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod of module root: %w", err)
+	}
+	return &goMod{uri: artifactURI(p), file: f}, nil
+}
+
+// locate returns the location of the require line for the module, falling back to the module directive for modules
+// that are not required (e.g. main modules)
+func (g *goMod) locate(modulePath string) Location {
+	loc := Location{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: g.uri}}}
+	for _, r := range g.file.Require {
+		if r.Mod.Path == modulePath && r.Syntax != nil {
+			loc.PhysicalLocation.Region = region(r.Syntax.Start, r.Syntax.End)
+			return loc
+		}
+	}
+	if g.file.Module != nil && g.file.Module.Syntax != nil {
+		loc.PhysicalLocation.Region = region(g.file.Module.Syntax.Start, g.file.Module.Syntax.End)
+	}
+	return loc
+}
+
+func region(start, end modfile.Position) *Region {
+	return &Region{
+		StartLine:   start.Line,
+		StartColumn: start.LineRune,
+		EndLine:     end.Line,
+		EndColumn:   end.LineRune,
+	}
+}
+
+// artifactURI returns the URI of the file: relative paths are left relative, so that code-scanning tools resolve them
+// against the repository root, while absolute paths are converted to file URIs
+func artifactURI(p string) string {
+	if !filepath.IsAbs(p) {
+		return filepath.ToSlash(filepath.Clean(p))
+	}
+	uri := filepath.ToSlash(p)
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri // e.g. windows drive letters
+	}
+	return "file://" + uri
+}
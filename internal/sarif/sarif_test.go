@@ -0,0 +1,266 @@
+package sarif_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/sarif"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+const goMod = `module github.com/acme/foo
+
+go 1.18
+
+require (
+	github.com/abc/xyz v1.0.0
+	github.com/def/uvw v0.3.0 // indirect
+)
+`
+
+func TestNew(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte(goMod), 0o644))
+	uri := "file://" + filepath.ToSlash(filepath.Join(root, "go.mod"))
+	location := func(line, endColumn int) []sarif.Location {
+		startColumn := 2
+		if line == 1 {
+			startColumn = 1
+		}
+		return []sarif.Location{{PhysicalLocation: sarif.PhysicalLocation{
+			ArtifactLocation: sarif.ArtifactLocation{URI: uri},
+			Region:           &sarif.Region{StartLine: line, StartColumn: startColumn, EndLine: line, EndColumn: endColumn},
+		}}}
+	}
+	ref := func(path string) model.ModuleReference {
+		return model.ModuleReference{Path: path, Version: "v1.0.0"}
+	}
+
+	testCases := []struct {
+		name       string
+		module     scan.EvaluatedModule
+		moduleRoot string
+		expected   []sarif.Result
+	}{
+		{
+			name:     "allowed",
+			module:   scan.EvaluatedModule{Module: model.Module{ModuleReference: ref("github.com/abc/xyz")}, Decision: scan.DecisionAllowed},
+			expected: []sarif.Result{},
+		},
+		{
+			name: "allowed, with info notices",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision: scan.DecisionAllowed,
+				Severity: scan.SeverityInfo,
+				Notices:  []scan.Notice{{Severity: scan.SeverityInfo, Message: "below threshold match for MIT (0.70 < 0.80)"}},
+			},
+			expected: []sarif.Result{},
+		},
+		{
+			name: "allowed, with an expired rule",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision: scan.DecisionAllowed,
+				Severity: scan.SeverityError,
+				Notices: []scan.Notice{
+					{Severity: scan.SeverityInfo, Message: "below threshold match for MIT (0.70 < 0.80)"},
+					{Severity: scan.SeverityError, Message: "override for github.com/abc/xyz expired on 2023-01-31"},
+				},
+			},
+			moduleRoot: root,
+			expected: []sarif.Result{{
+				RuleID:    "notices",
+				RuleIndex: 6,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: allowed; override for github.com/abc/xyz expired on 2023-01-31"},
+				Locations: location(6, 27),
+			}},
+		},
+		{
+			name: "allowed, with a warning",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision: scan.DecisionAllowed,
+				Severity: scan.SeverityWarn,
+				Notices:  []scan.Notice{{Severity: scan.SeverityWarn, Message: "low confidence match for MIT (0.85)"}},
+			},
+			expected: []sarif.Result{{
+				RuleID:    "notices",
+				RuleIndex: 6,
+				Level:     "warning",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: allowed; low confidence match for MIT (0.85)"},
+			}},
+		},
+		{
+			name: "approved by module rule",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision: scan.DecisionAllowedModuleApproved,
+				Rules:    []scan.Rule{{Type: scan.RuleTypeModuleAllow, Path: "github.com/abc/xyz"}},
+			},
+			expected: []sarif.Result{},
+		},
+		{
+			name: "located at require line",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+			},
+			moduleRoot: root,
+			expected: []sarif.Result{{
+				RuleID:    "licenses-not-allowed",
+				RuleIndex: 0,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: not allowed - non-permitted licenses: [GPL-3.0]"},
+				Locations: location(6, 27),
+			}},
+		},
+		{
+			name: "without module root",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+			},
+			expected: []sarif.Result{{
+				RuleID:    "licenses-not-allowed",
+				RuleIndex: 0,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: not allowed - non-permitted licenses: [GPL-3.0]"},
+			}},
+		},
+		{
+			name: "baselined",
+			module: scan.EvaluatedModule{
+				Module:    model.Module{ModuleReference: model.ModuleReference{Path: "github.com/def/uvw", Version: "v0.3.0"}},
+				Decision:  scan.DecisionNotAllowedNoLicenseFiles,
+				Severity:  scan.SeverityWarn,
+				Baselined: true,
+			},
+			moduleRoot: root,
+			expected: []sarif.Result{{
+				RuleID:       "no-license-files",
+				RuleIndex:    2,
+				Level:        "warning",
+				Message:      sarif.Message{Text: "github.com/def/uvw@v0.3.0: not allowed - no license files found"},
+				Locations:    location(7, 27),
+				Suppressions: []sarif.Suppression{{Kind: "external", Justification: "accepted by baseline"}},
+			}},
+		},
+		{
+			name: "main module located at module directive",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: model.ModuleReference{Path: "github.com/acme/foo", Version: "v1.1.0"}, Main: true},
+				Decision: scan.DecisionNotAllowedModuleUnavailable,
+				Severity: scan.SeverityError,
+			},
+			moduleRoot: root,
+			expected: []sarif.Result{{
+				RuleID:    "module-unavailable",
+				RuleIndex: 4,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/acme/foo@v1.1.0: not allowed - module source unavailable"},
+				Locations: location(1, 27),
+			}},
+		},
+		{
+			name: "denied by module rule, at info severity",
+			module: scan.EvaluatedModule{
+				Module:   model.Module{ModuleReference: ref("github.com/not/required")},
+				Decision: scan.DecisionNotAllowedModuleDenied,
+				Severity: scan.SeverityInfo,
+				Rules:    []scan.Rule{{Type: scan.RuleTypeModuleDeny, Path: "github.com/not/*", Metadata: scan.Metadata{Justification: "abandoned"}}},
+			},
+			moduleRoot: root,
+			expected: []sarif.Result{{
+				RuleID:    "module-denied",
+				RuleIndex: 1,
+				Level:     "note",
+				Message:   sarif.Message{Text: "github.com/not/required@v1.0.0: not allowed - module denied by rule for github.com/not/* (abandoned)"},
+				Locations: location(1, 27),
+			}},
+		},
+		{
+			name: "not allowed for one of its binaries",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref("github.com/abc/xyz")},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			expected: []sarif.Result{{
+				RuleID:    "licenses-not-allowed",
+				RuleIndex: 0,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: not allowed - non-permitted licenses: [GPL-3.0]"},
+			}},
+		},
+		{
+			name: "unclassified, with a best guess",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: ref("github.com/abc/xyz"),
+					BelowThreshold:  []model.License{{Name: "MIT", Path: "/mod/LICENSE", Confidence: 0.5}},
+				},
+				Decision: scan.DecisionNotAllowedUnclassifiedLicense,
+				Severity: scan.SeverityError,
+			},
+			expected: []sarif.Result{{
+				RuleID:    "unclassified-license",
+				RuleIndex: 3,
+				Level:     "error",
+				Message:   sarif.Message{Text: "github.com/abc/xyz@v1.0.0: not allowed - license files could not be classified (best guess: MIT, confidence 0.50)"},
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			log, err := sarif.New(scan.Summary{Modules: []scan.EvaluatedModule{tc.module}}, tc.moduleRoot)
+			require.NoError(t, err)
+			require.Len(t, log.Runs, 1)
+			assert.Equal(t, tc.expected, log.Runs[0].Results)
+		})
+	}
+}
+
+func TestNew_Rules(t *testing.T) {
+	log, err := sarif.New(scan.Summary{}, "")
+	require.NoError(t, err)
+	require.Len(t, log.Runs, 1)
+
+	driver := log.Runs[0].Tool.Driver
+	assert.Equal(t, "lichen", driver.Name)
+	var ids []string
+	for _, r := range driver.Rules {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{
+		"licenses-not-allowed",
+		"module-denied",
+		"no-license-files",
+		"unclassified-license",
+		"module-unavailable",
+		"unresolvable-license",
+		"notices",
+	}, ids)
+}
+
+func TestNew_MissingGoMod(t *testing.T) {
+	_, err := sarif.New(scan.Summary{}, t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON writes the log in the SARIF JSON format
+func WriteJSON(w io.Writer, log Log) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+type cdxComponent struct {
+	PURL       string         `json:"purl" xml:"purl"`
+	Licenses   []cdxLicense   `json:"licenses" xml:"licenses>license"`
+	Expression string         `json:"-" xml:"licenses>expression"`
+	Components []cdxComponent `json:"components" xml:"components>component"`
+}
+
+// cdxLicense is a license choice, which in JSON is either a license or an expression
+type cdxLicense struct {
+	License *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"license"`
+	Expression string `json:"expression"`
+
+	// XML license elements are not wrapped in a license choice
+	ID   string `json:"-" xml:"id"`
+	Name string `json:"-" xml:"name"`
+}
+
+type cdxBOM struct {
+	Metadata struct {
+		Component *cdxComponent `json:"component" xml:"component"`
+	} `json:"metadata" xml:"metadata"`
+	Components []cdxComponent `json:"components" xml:"components>component"`
+}
+
+func parseCycloneDXJSON(b []byte) (SBOM, error) {
+	var bom cdxBOM
+	if err := json.Unmarshal(b, &bom); err != nil {
+		return SBOM{}, err
+	}
+	return bom.sbom(), nil
+}
+
+func parseCycloneDXXML(b []byte) (SBOM, error) {
+	var bom cdxBOM
+	if err := xml.Unmarshal(b, &bom); err != nil {
+		return SBOM{}, err
+	}
+	return bom.sbom(), nil
+}
+
+func (bom cdxBOM) sbom() SBOM {
+	var b builder
+	if c := bom.Metadata.Component; c != nil {
+		if ref, ok := parsePURL(c.PURL); ok {
+			b.sbom.Main = ref
+		}
+		b.addComponents(c.Components)
+	}
+	b.addComponents(bom.Components)
+	return b.sbom
+}
+
+// addComponents adds the components, along with any nested components
+func (b *builder) addComponents(components []cdxComponent) {
+	for _, c := range components {
+		b.add(c.PURL, c.declared())
+		b.addComponents(c.Components)
+	}
+}
+
+func (c cdxComponent) declared() []string {
+	var names []string
+	if c.Expression != "" {
+		names = append(names, licenseNames(c.Expression)...)
+	}
+	for _, l := range c.Licenses {
+		switch {
+		case l.License != nil && l.License.ID != "":
+			names = append(names, l.License.ID)
+		case l.License != nil && l.License.Name != "":
+			names = append(names, l.License.Name)
+		case l.Expression != "":
+			names = append(names, licenseNames(l.Expression)...)
+		case l.ID != "":
+			names = append(names, l.ID)
+		case l.Name != "":
+			names = append(names, l.Name)
+		}
+	}
+	return names
+}
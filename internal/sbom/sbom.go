@@ -0,0 +1,173 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// SBOM carries the Go modules listed by a software bill of materials, which is scanned in place of a binary
+type SBOM struct {
+	Path       string                // OS level absolute path to the SBOM
+	Main       model.ModuleReference // the module described by the SBOM, if it is a Go module
+	Components []Component           // Go modules listed by the SBOM, excluding the main module
+}
+
+// Component is a Go module listed by an SBOM, along with the licenses it declares for the module
+type Component struct {
+	model.ModuleReference
+	Declared []string // SPDX names of the declared licenses, nil if the SBOM makes no assertion
+}
+
+// BuildInfo returns the build info equivalent of the SBOM, so that it can be evaluated in the same way as a binary
+func (s SBOM) BuildInfo() model.BuildInfo {
+	info := model.BuildInfo{
+		Path:          s.Path,
+		ModulePath:    s.Main.Path,
+		ModuleVersion: s.Main.Version,
+		ModuleRefs:    make([]model.ModuleReference, 0, len(s.Components)),
+	}
+	for _, c := range s.Components {
+		info.ModuleRefs = append(info.ModuleRefs, c.ModuleReference)
+	}
+	return info
+}
+
+// Read reads the SBOM at the supplied path, which can be a CycloneDX (JSON or XML) or SPDX (JSON or tag-value)
+// document
+func Read(path string) (SBOM, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return SBOM{}, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	s, err := Parse(b)
+	if err != nil {
+		return SBOM{}, fmt.Errorf("failed to parse SBOM %s: %w", path, err)
+	}
+	s.Path = path
+	return s, nil
+}
+
+// Parse parses an SBOM, detecting its format from its contents
+func Parse(b []byte) (SBOM, error) {
+	trimmed := bytes.TrimSpace(b)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var probe struct {
+			BOMFormat   string `json:"bomFormat"`
+			SPDXVersion string `json:"spdxVersion"`
+		}
+		if err := json.Unmarshal(trimmed, &probe); err != nil {
+			return SBOM{}, err
+		}
+		switch {
+		case probe.BOMFormat == "CycloneDX":
+			return parseCycloneDXJSON(trimmed)
+		case probe.SPDXVersion != "":
+			return parseSPDXJSON(trimmed)
+		}
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseCycloneDXXML(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("SPDXVersion:")):
+		return parseSPDXTagValue(trimmed)
+	}
+	return SBOM{}, fmt.Errorf("unrecognised format (expected CycloneDX JSON or XML, or SPDX JSON or tag-value)")
+}
+
+// builder accumulates the Go modules of an SBOM, merging the declared licenses of modules listed more than once
+type builder struct {
+	sbom  SBOM
+	index map[model.ModuleReference]int
+}
+
+func (b *builder) add(purl string, declared []string) {
+	ref, ok := parsePURL(purl)
+	if !ok || !semver.IsValid(ref.Version) {
+		return
+	}
+	if b.index == nil {
+		b.index = make(map[model.ModuleReference]int)
+	}
+	if i, found := b.index[ref]; found {
+		b.sbom.Components[i].Declared = merge(b.sbom.Components[i].Declared, declared)
+		return
+	}
+	b.index[ref] = len(b.sbom.Components)
+	b.sbom.Components = append(b.sbom.Components, Component{ModuleReference: ref, Declared: merge(nil, declared)})
+}
+
+// parsePURL returns the module referenced by a Go package URL (pkg:golang/...). Package URLs of other types, and those
+// that do not reference a valid module path (e.g. pkg:golang/stdlib), are ignored.
+func parsePURL(purl string) (model.ModuleReference, bool) {
+	const prefix = "pkg:golang/"
+	if len(purl) < len(prefix) || !strings.EqualFold(purl[:len(prefix)], prefix) {
+		return model.ModuleReference{}, false
+	}
+	s := purl[len(prefix):]
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+
+	var ref model.ModuleReference
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		version, err := url.PathUnescape(s[i+1:])
+		if err != nil {
+			return model.ModuleReference{}, false
+		}
+		s, ref.Version = s[:i], version
+	}
+	segments := strings.Split(s, "/")
+	for i, seg := range segments {
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			return model.ModuleReference{}, false
+		}
+		segments[i] = unescaped
+	}
+	ref.Path = strings.Join(segments, "/")
+	if module.CheckPath(ref.Path) != nil {
+		return model.ModuleReference{}, false
+	}
+	return ref, true
+}
+
+// licenseNames returns the license names referenced by an SPDX license expression, ignoring operators and exceptions
+func licenseNames(expression string) []string {
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(expression))
+	var names []string
+	for i := 0; i < len(fields); i++ {
+		switch strings.ToUpper(fields[i]) {
+		case "AND", "OR":
+		case "WITH":
+			i++ // skip the exception
+		default:
+			names = append(names, fields[i])
+		}
+	}
+	return names
+}
+
+// merge returns the unique, sorted union of the supplied license names
+func merge(a, b []string) []string {
+	if a == nil && b == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(a)+len(b))
+	merged := make([]string, 0, len(a)+len(b))
+	for _, name := range append(append([]string{}, a...), b...) {
+		if !seen[name] {
+			seen[name] = true
+			merged = append(merged, name)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -0,0 +1,174 @@
+package sbom_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/sbom"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected sbom.SBOM
+		err      string
+	}{
+		{
+			name: "CycloneDX JSON",
+			input: `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {
+    "component": {"type": "application", "name": "foo", "purl": "pkg:golang/github.com/acme/foo@v1.0.0"}
+  },
+  "components": [
+    {
+      "type": "library",
+      "name": "github.com/abc/xyz",
+      "purl": "pkg:golang/github.com/abc/xyz@v1.2.0%2Bincompatible?type=module",
+      "licenses": [{"license": {"id": "MIT"}}, {"license": {"name": "LicenseRef-Custom"}}],
+      "components": [
+        {"type": "library", "purl": "pkg:golang/github.com/abc/xyz/nested@v0.1.0", "licenses": [{"expression": "Apache-2.0 OR (MIT AND BSD-3-Clause)"}]}
+      ]
+    },
+    {"type": "library", "purl": "pkg:golang/github.com/no/licenses@v0.2.0"},
+    {"type": "library", "purl": "pkg:golang/stdlib@go1.21.0"},
+    {"type": "library", "purl": "pkg:npm/left-pad@1.3.0"}
+  ]
+}`,
+			expected: sbom.SBOM{
+				Main: model.ModuleReference{Path: "github.com/acme/foo", Version: "v1.0.0"},
+				Components: []sbom.Component{
+					{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.2.0+incompatible"}, Declared: []string{"LicenseRef-Custom", "MIT"}},
+					{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz/nested", Version: "v0.1.0"}, Declared: []string{"Apache-2.0", "BSD-3-Clause", "MIT"}},
+					{ModuleReference: model.ModuleReference{Path: "github.com/no/licenses", Version: "v0.2.0"}},
+				},
+			},
+		},
+		{
+			name: "CycloneDX XML",
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">
+  <components>
+    <component type="library">
+      <name>github.com/abc/xyz</name>
+      <licenses>
+        <license><id>MIT</id></license>
+      </licenses>
+      <purl>pkg:golang/github.com/abc/xyz@v1.0.0</purl>
+    </component>
+    <component type="library">
+      <licenses>
+        <expression>Apache-2.0 WITH LLVM-exception</expression>
+      </licenses>
+      <purl>pkg:golang/github.com/def/uvw@v0.3.0</purl>
+    </component>
+  </components>
+</bom>`,
+			expected: sbom.SBOM{
+				Components: []sbom.Component{
+					{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}, Declared: []string{"MIT"}},
+					{ModuleReference: model.ModuleReference{Path: "github.com/def/uvw", Version: "v0.3.0"}, Declared: []string{"Apache-2.0"}},
+				},
+			},
+		},
+		{
+			name: "SPDX JSON",
+			input: `{
+  "spdxVersion": "SPDX-2.3",
+  "documentDescribes": ["SPDXRef-Package-foo"],
+  "packages": [
+    {
+      "SPDXID": "SPDXRef-Package-foo",
+      "licenseDeclared": "MIT",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/acme/foo@v1.0.0"}]
+    },
+    {
+      "SPDXID": "SPDXRef-Package-xyz",
+      "licenseDeclared": "NOASSERTION",
+      "licenseConcluded": "(Apache-2.0 AND MIT)",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/abc/xyz@v1.2.0"}]
+    },
+    {
+      "SPDXID": "SPDXRef-Package-uvw",
+      "licenseDeclared": "NOASSERTION",
+      "licenseConcluded": "NOASSERTION",
+      "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:golang/github.com/def/uvw@v0.3.0"}]
+    }
+  ]
+}`,
+			expected: sbom.SBOM{
+				Main: model.ModuleReference{Path: "github.com/acme/foo", Version: "v1.0.0"},
+				Components: []sbom.Component{
+					{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.2.0"}, Declared: []string{"Apache-2.0", "MIT"}},
+					{ModuleReference: model.ModuleReference{Path: "github.com/def/uvw", Version: "v0.3.0"}},
+				},
+			},
+		},
+		{
+			name: "SPDX tag-value",
+			input: `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+
+PackageName: foo
+SPDXID: SPDXRef-Binary-foo
+PackageDownloadLocation: NOASSERTION
+PackageLicenseDeclared: NOASSERTION
+
+PackageName: github.com/abc/xyz
+SPDXID: SPDXRef-Module-github.com-abc-xyz-v1.0.0
+PackageLicenseConcluded: MIT
+PackageLicenseDeclared: MIT
+PackageCopyrightText: <text>Copyright 2020 A
+PackageLicenseDeclared: Apache-2.0</text>
+ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/abc/xyz@v1.0.0
+
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Binary-foo
+Relationship: SPDXRef-Binary-foo DEPENDS_ON SPDXRef-Module-github.com-abc-xyz-v1.0.0
+`,
+			expected: sbom.SBOM{
+				Components: []sbom.Component{
+					{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}, Declared: []string{"MIT"}},
+				},
+			},
+		},
+		{
+			name:  "unrecognised format",
+			input: `name: foo`,
+			err:   "unrecognised format (expected CycloneDX JSON or XML, or SPDX JSON or tag-value)",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := sbom.Parse([]byte(tc.input))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s)
+		})
+	}
+}
+
+func TestSBOM_BuildInfo(t *testing.T) {
+	s := sbom.SBOM{
+		Path: "/sboms/foo.cdx.json",
+		Main: model.ModuleReference{Path: "github.com/acme/foo", Version: "v1.0.0"},
+		Components: []sbom.Component{
+			{ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}, Declared: []string{"MIT"}},
+		},
+	}
+	assert.Equal(t, model.BuildInfo{
+		Path:          "/sboms/foo.cdx.json",
+		ModulePath:    "github.com/acme/foo",
+		ModuleVersion: "v1.0.0",
+		ModuleRefs:    []model.ModuleReference{{Path: "github.com/abc/xyz", Version: "v1.0.0"}},
+	}, s.BuildInfo())
+}
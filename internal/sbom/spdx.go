@@ -0,0 +1,174 @@
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const documentID = "SPDXRef-DOCUMENT"
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceType    string `json:"referenceType"`
+	ReferenceLocator string `json:"referenceLocator"`
+}
+
+// purl returns the package URL of the package, if it has one
+func (p spdxPackage) purl() string {
+	for _, ref := range p.ExternalRefs {
+		if ref.ReferenceType == "purl" {
+			return ref.ReferenceLocator
+		}
+	}
+	return ""
+}
+
+// declared returns the declared licenses of the package, falling back to the concluded licenses if the declared
+// licenses are not asserted
+func (p spdxPackage) declared() []string {
+	for _, expression := range []string{p.LicenseDeclared, p.LicenseConcluded} {
+		switch expression {
+		case "", "NOASSERTION", "NONE":
+			continue
+		}
+		return licenseNames(expression)
+	}
+	return nil
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxDocument struct {
+	DocumentDescribes []string           `json:"documentDescribes"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+func parseSPDXJSON(b []byte) (SBOM, error) {
+	var doc spdxDocument
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return SBOM{}, err
+	}
+	return doc.sbom(), nil
+}
+
+func parseSPDXTagValue(b []byte) (SBOM, error) {
+	var (
+		doc     spdxDocument
+		current *spdxPackage
+		scanner = bufio.NewScanner(bytes.NewReader(b))
+	)
+	scanner.Buffer(nil, 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return SBOM{}, fmt.Errorf("invalid tag-value line %d: %s", n, line)
+		}
+		tag, value := parts[0], strings.TrimSpace(parts[1])
+
+		// skip over multi-line values, none of which are of interest
+		if strings.HasPrefix(value, "<text>") {
+			for !strings.Contains(line, "</text>") && scanner.Scan() {
+				line = scanner.Text()
+				n++
+			}
+			continue
+		}
+
+		switch tag {
+		case "PackageName":
+			doc.Packages = append(doc.Packages, spdxPackage{})
+			current = &doc.Packages[len(doc.Packages)-1]
+		case "SPDXID":
+			if current != nil {
+				current.SPDXID = value
+			}
+		case "PackageLicenseDeclared":
+			if current != nil {
+				current.LicenseDeclared = value
+			}
+		case "PackageLicenseConcluded":
+			if current != nil {
+				current.LicenseConcluded = value
+			}
+		case "ExternalRef":
+			if fields := strings.Fields(value); current != nil && len(fields) == 3 {
+				current.ExternalRefs = append(current.ExternalRefs, spdxExternalRef{
+					ReferenceType:    fields[1],
+					ReferenceLocator: fields[2],
+				})
+			}
+		case "FileName", "SnippetSPDXID", "LicenseID":
+			// files, snippets and extracted licenses follow packages, and their tags must not be attributed to them
+			current = nil
+		case "Relationship":
+			if fields := strings.Fields(value); len(fields) == 3 {
+				doc.Relationships = append(doc.Relationships, spdxRelationship{
+					SPDXElementID:      fields[0],
+					RelationshipType:   fields[1],
+					RelatedSPDXElement: fields[2],
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return SBOM{}, err
+	}
+	return doc.sbom(), nil
+}
+
+// sbom returns the Go modules of the document. If the document describes a single Go module, it is treated as the
+// main module.
+func (doc spdxDocument) sbom() SBOM {
+	described := make(map[string]bool)
+	for _, id := range doc.DocumentDescribes {
+		described[id] = true
+	}
+	for _, r := range doc.Relationships {
+		switch {
+		case r.SPDXElementID == documentID && r.RelationshipType == "DESCRIBES":
+			described[r.RelatedSPDXElement] = true
+		case r.RelatedSPDXElement == documentID && r.RelationshipType == "DESCRIBED_BY":
+			described[r.SPDXElementID] = true
+		}
+	}
+
+	var (
+		b    builder
+		main *spdxPackage
+	)
+	for i, p := range doc.Packages {
+		if _, ok := parsePURL(p.purl()); ok && described[p.SPDXID] {
+			if main != nil {
+				main = nil
+				break
+			}
+			main = &doc.Packages[i]
+		}
+	}
+	for _, p := range doc.Packages {
+		if main != nil && p.SPDXID == main.SPDXID {
+			b.sbom.Main, _ = parsePURL(p.purl())
+			continue
+		}
+		b.add(p.purl(), p.declared())
+	}
+	return b.sbom
+}
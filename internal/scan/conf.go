@@ -1,30 +1,283 @@
 package scan
 
+import (
+	"fmt"
+	"time"
+)
+
 type Config struct {
-	Threshold  *float64   `yaml:"threshold"`
-	Allow      []string   `yaml:"allow"`
-	Exceptions Exceptions `yaml:"exceptions"`
-	Overrides  []Override `yaml:"override"`
+	Extends           []string            `yaml:"extends,omitempty"` // paths to base configs, relative to the config itself
+	Threshold         *float64            `yaml:"threshold,omitempty"`
+	Thresholds        map[string]float64  `yaml:"thresholds,omitempty"` // per license overrides of the threshold
+	Allow             []string            `yaml:"allow,omitempty"`
+	Deny              []string            `yaml:"deny,omitempty"`
+	Categories        map[string][]string `yaml:"categories,omitempty"`
+	Exceptions        Exceptions          `yaml:"exceptions,omitempty"`
+	Overrides         []Override          `yaml:"override,omitempty"`
+	Severity          Severities          `yaml:"severity,omitempty"`
+	RequireMetadata   []string            `yaml:"requireMetadata,omitempty"`   // metadata fields every override and exception must set
+	ExpiryWarningDays int                 `yaml:"expiryWarningDays,omitempty"` // days before expiry that rules start to produce warnings
+	Policies          []Policy            `yaml:"policies,omitempty"`
+	Modules           ModuleRules         `yaml:"modules,omitempty"`
+}
+
+// ModuleRules allow or deny modules regardless of their licenses, e.g. to approve a vendor module covered by a
+// commercial agreement, or to block a compromised fork. Where both an allow and a deny rule match a module, the most
+// specific wins, with deny rules winning ties.
+type ModuleRules struct {
+	Allow []ModuleRule `yaml:"allow,omitempty"`
+	Deny  []ModuleRule `yaml:"deny,omitempty"`
+}
+
+type ModuleRule struct {
+	Path     string `yaml:"path"`
+	Version  string `yaml:"version,omitempty"`
+	Metadata `yaml:",inline"`
+}
+
+// Policy scopes allow and deny lists, and exceptions, to particular binaries. A binary is evaluated against the first
+// policy that selects it, or the top-level config if no policy does.
+type Policy struct {
+	Name       string     `yaml:"name"`
+	Binaries   []string   `yaml:"binaries"`             // patterns matched against each binary's OS path, package path and module path
+	Allow      []string   `yaml:"allow,omitempty"`      // replaces the top-level allow list, if specified
+	Deny       []string   `yaml:"deny,omitempty"`       // replaces the top-level deny list, if specified
+	Exceptions Exceptions `yaml:"exceptions,omitempty"` // added to the top-level exceptions
+}
+
+// Severities configures how violations of each policy rule are treated. Unless otherwise specified, violations
+// have an error severity.
+type Severities struct {
+	UnresolvableLicense Severity            `yaml:"unresolvableLicense,omitempty"`
+	LicenseNotPermitted Severity            `yaml:"licenseNotPermitted,omitempty"`
+	Licenses            map[string]Severity `yaml:"licenses,omitempty"`
+	LowConfidence       *LowConfidence      `yaml:"lowConfidence,omitempty"`
+	StaleRules          Severity            `yaml:"staleRules,omitempty"`   // defaults to warn
+	ModuleDenied        Severity            `yaml:"moduleDenied,omitempty"` // defaults to error
+}
+
+// LowConfidence flags license matches with a confidence below the configured threshold
+type LowConfidence struct {
+	Threshold float64  `yaml:"threshold,omitempty"`
+	Severity  Severity `yaml:"severity,omitempty"`
 }
 
 type Exceptions struct {
-	LicenseNotPermitted []LicenseNotPermitted `yaml:"licenseNotPermitted"`
-	UnresolvableLicense []UnresolvableLicense `yaml:"unresolvableLicense"`
+	LicenseNotPermitted []LicenseNotPermitted `yaml:"licenseNotPermitted,omitempty"`
+	UnresolvableLicense []UnresolvableLicense `yaml:"unresolvableLicense,omitempty"` // covers each of the reasons below
+	NoLicenseFiles      []UnresolvableLicense `yaml:"noLicenseFiles,omitempty"`
+	UnclassifiedLicense []UnresolvableLicense `yaml:"unclassifiedLicense,omitempty"`
+	ModuleUnavailable   []UnresolvableLicense `yaml:"moduleUnavailable,omitempty"`
 }
 
 type LicenseNotPermitted struct {
 	Path     string   `yaml:"path"`
-	Version  string   `yaml:"version"`
-	Licenses []string `yaml:"licenses"`
+	Version  string   `yaml:"version,omitempty"`
+	Licenses []string `yaml:"licenses,omitempty"`
+	Metadata `yaml:",inline"`
 }
 
 type UnresolvableLicense struct {
-	Path    string `yaml:"path"`
-	Version string `yaml:"version"`
+	Path     string `yaml:"path"`
+	Version  string `yaml:"version,omitempty"`
+	Metadata `yaml:",inline"`
 }
 
 type Override struct {
 	Path     string   `yaml:"path"`
-	Version  string   `yaml:"version"`
-	Licenses []string `yaml:"licenses"`
+	Version  string   `yaml:"version,omitempty"`
+	Licenses []string `yaml:"licenses,omitempty"`
+	Metadata `yaml:",inline"`
+}
+
+// Metadata records why an override or exception exists, who is responsible for it and when it lapses
+type Metadata struct {
+	Justification string `yaml:"justification,omitempty" json:",omitempty"`
+	Owner         string `yaml:"owner,omitempty" json:",omitempty"`
+	Reference     string `yaml:"reference,omitempty" json:",omitempty"` // link to a ticket, agreement or similar
+	Expires       *Date  `yaml:"expires,omitempty" json:",omitempty"`   // date after which the rule no longer applies
+}
+
+// missing returns the names of required fields that have not been set
+func (m Metadata) missing(required []string) (missing []string, err error) {
+	for _, field := range required {
+		var set bool
+		switch field {
+		case "justification":
+			set = m.Justification != ""
+		case "owner":
+			set = m.Owner != ""
+		case "reference":
+			set = m.Reference != ""
+		case "expires":
+			set = m.Expires != nil
+		default:
+			return nil, fmt.Errorf("unrecognised metadata field %q (expected one of: justification, owner, reference, expires)", field)
+		}
+		if !set {
+			missing = append(missing, field)
+		}
+	}
+	return missing, nil
+}
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date, represented in config and output as YYYY-MM-DD
+type Date struct {
+	t time.Time
+}
+
+// Time returns the start of the date (UTC)
+func (d Date) Time() time.Time {
+	return d.t
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(b []byte) error {
+	t, err := time.Parse(dateLayout, string(b))
+	if err != nil {
+		return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", string(b))
+	}
+	d.t = t
+	return nil
+}
+
+// String returns the date formatted as YYYY-MM-DD
+func (d Date) String() string {
+	return d.t.Format(dateLayout)
+}
+
+// scoped returns the config applicable to binaries selected by the supplied policy
+func (c Config) scoped(p Policy) Config {
+	scoped := c
+	scoped.Policies = nil
+	if len(p.Allow) > 0 {
+		scoped.Allow = p.Allow
+	}
+	if len(p.Deny) > 0 {
+		scoped.Deny = p.Deny
+	}
+	return scoped
+}
+
+// Merge returns the result of layering the overlay config on top of the base config. Lists are concatenated (with
+// entries from the base first), maps are merged (with the overlay taking precedence for duplicate keys, except for
+// categories, whose licenses are combined), and scalar values in the overlay replace those in the base when set.
+// The extends field is not carried over to the result. As allow lists are concatenated, an overlay can only widen the
+// allow list of its base, and must deny licenses to narrow it.
+func (c Config) Merge(overlay Config) Config {
+	merged := Config{
+		Threshold:  c.Threshold,
+		Thresholds: mergeThresholds(c.Thresholds, overlay.Thresholds),
+		Allow:      appendUnique(c.Allow, overlay.Allow...),
+		Deny:       appendUnique(c.Deny, overlay.Deny...),
+		Categories: make(map[string][]string, len(c.Categories)+len(overlay.Categories)),
+		Exceptions: Exceptions{
+			LicenseNotPermitted: append(append([]LicenseNotPermitted(nil), c.Exceptions.LicenseNotPermitted...), overlay.Exceptions.LicenseNotPermitted...),
+			UnresolvableLicense: append(append([]UnresolvableLicense(nil), c.Exceptions.UnresolvableLicense...), overlay.Exceptions.UnresolvableLicense...),
+			NoLicenseFiles:      append(append([]UnresolvableLicense(nil), c.Exceptions.NoLicenseFiles...), overlay.Exceptions.NoLicenseFiles...),
+			UnclassifiedLicense: append(append([]UnresolvableLicense(nil), c.Exceptions.UnclassifiedLicense...), overlay.Exceptions.UnclassifiedLicense...),
+			ModuleUnavailable:   append(append([]UnresolvableLicense(nil), c.Exceptions.ModuleUnavailable...), overlay.Exceptions.ModuleUnavailable...),
+		},
+		Overrides:         append(append([]Override(nil), c.Overrides...), overlay.Overrides...),
+		Severity:          c.Severity.merge(overlay.Severity),
+		RequireMetadata:   appendUnique(c.RequireMetadata, overlay.RequireMetadata...),
+		ExpiryWarningDays: c.ExpiryWarningDays,
+		Policies:          mergePolicies(c.Policies, overlay.Policies),
+		Modules: ModuleRules{
+			Allow: append(append([]ModuleRule(nil), c.Modules.Allow...), overlay.Modules.Allow...),
+			Deny:  append(append([]ModuleRule(nil), c.Modules.Deny...), overlay.Modules.Deny...),
+		},
+	}
+	if overlay.Threshold != nil {
+		merged.Threshold = overlay.Threshold
+	}
+	if overlay.ExpiryWarningDays != 0 {
+		merged.ExpiryWarningDays = overlay.ExpiryWarningDays
+	}
+	for _, categories := range []map[string][]string{c.Categories, overlay.Categories} {
+		for category, licenses := range categories {
+			merged.Categories[category] = appendUnique(merged.Categories[category], licenses...)
+		}
+	}
+	if len(merged.Categories) == 0 {
+		merged.Categories = nil
+	}
+	return merged
+}
+
+func (s Severities) merge(overlay Severities) Severities {
+	merged := Severities{
+		UnresolvableLicense: overlay.UnresolvableLicense.or(s.UnresolvableLicense),
+		LicenseNotPermitted: overlay.LicenseNotPermitted.or(s.LicenseNotPermitted),
+		LowConfidence:       s.LowConfidence,
+		StaleRules:          overlay.StaleRules.or(s.StaleRules),
+		ModuleDenied:        overlay.ModuleDenied.or(s.ModuleDenied),
+	}
+	if overlay.LowConfidence != nil {
+		merged.LowConfidence = overlay.LowConfidence
+	}
+	for _, licenses := range []map[string]Severity{s.Licenses, overlay.Licenses} {
+		for lic, severity := range licenses {
+			if merged.Licenses == nil {
+				merged.Licenses = make(map[string]Severity)
+			}
+			merged.Licenses[lic] = severity
+		}
+	}
+	return merged
+}
+
+// mergeThresholds combines the thresholds, with the overlay taking precedence for the same license
+func mergeThresholds(base, overlay map[string]float64) map[string]float64 {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+	merged := make(map[string]float64, len(base)+len(overlay))
+	for _, thresholds := range []map[string]float64{base, overlay} {
+		for lic, threshold := range thresholds {
+			merged[lic] = threshold
+		}
+	}
+	return merged
+}
+
+// mergePolicies appends the overlay policies to the base policies, replacing base policies of the same name in place
+func mergePolicies(base, overlay []Policy) []Policy {
+	merged := append([]Policy(nil), base...)
+	for _, p := range overlay {
+		var replaced bool
+		for i, existing := range merged {
+			if existing.Name == p.Name {
+				merged[i], replaced = p, true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+// appendUnique appends values not already present
+func appendUnique(list []string, values ...string) []string {
+	result := append([]string(nil), list...)
+	for _, v := range values {
+		var found bool
+		for _, existing := range result {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, v)
+		}
+	}
+	return result
 }
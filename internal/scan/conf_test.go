@@ -0,0 +1,147 @@
+package scan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+func TestConfig_Merge(t *testing.T) {
+	baseThreshold, overlayThreshold := 0.9, 0.95
+	base := scan.Config{
+		Extends:    []string{"other.yaml"},
+		Threshold:  &baseThreshold,
+		Thresholds: map[string]float64{"BSD-3-Clause": 0.95, "MIT": 0.7},
+		Allow:      []string{"permissive", "MPL-2.0"},
+		Deny:       []string{"network-copyleft"},
+		Categories: map[string][]string{"permissive": {"Foo-1.0"}},
+		Overrides:  []scan.Override{{Path: "github.com/foo/bar", Licenses: []string{"MIT"}}},
+		Severity: scan.Severities{
+			LicenseNotPermitted: scan.SeverityWarn,
+			Licenses:            map[string]scan.Severity{"weak-copyleft": scan.SeverityWarn},
+		},
+		ExpiryWarningDays: 30,
+	}
+	overlay := scan.Config{
+		Threshold:  &overlayThreshold,
+		Thresholds: map[string]float64{"MIT": 0.75},
+		Allow:      []string{"MPL-2.0", "ISC"},
+		Categories: map[string][]string{"permissive": {"Bar-1.0"}},
+		Overrides:  []scan.Override{{Path: "github.com/baz/qux", Licenses: []string{"ISC"}}},
+		Severity: scan.Severities{
+			Licenses: map[string]scan.Severity{"weak-copyleft": scan.SeverityError},
+		},
+	}
+
+	expected := scan.Config{
+		Threshold:  &overlayThreshold,
+		Thresholds: map[string]float64{"BSD-3-Clause": 0.95, "MIT": 0.75},
+		Allow:      []string{"permissive", "MPL-2.0", "ISC"},
+		Deny:       []string{"network-copyleft"},
+		Categories: map[string][]string{"permissive": {"Foo-1.0", "Bar-1.0"}},
+		Overrides: []scan.Override{
+			{Path: "github.com/foo/bar", Licenses: []string{"MIT"}},
+			{Path: "github.com/baz/qux", Licenses: []string{"ISC"}},
+		},
+		Severity: scan.Severities{
+			LicenseNotPermitted: scan.SeverityWarn,
+			Licenses:            map[string]scan.Severity{"weak-copyleft": scan.SeverityError},
+		},
+		ExpiryWarningDays: 30,
+	}
+	assert.Equal(t, expected, base.Merge(overlay))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	threshold := 1.2
+	testCases := []struct {
+		name        string
+		conf        scan.Config
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			conf: scan.Config{
+				Allow:      []string{"MIT", "weak-copyleft", "approved-commercial", "LicenseRef-Internal"},
+				Categories: map[string][]string{"approved-commercial": {"Acme-1.0"}},
+				Overrides:  []scan.Override{{Path: "github.com/foo/**", Version: "~v1", Licenses: []string{"Acme-1.0"}}},
+			},
+		},
+		{
+			name:        "threshold out of range",
+			conf:        scan.Config{Threshold: &threshold},
+			expectedErr: "threshold: must be greater than 0 and at most 1, received 1.2",
+		},
+		{
+			name:        "license threshold out of range",
+			conf:        scan.Config{Thresholds: map[string]float64{"BSD-3-Clause": 0}},
+			expectedErr: "thresholds.BSD-3-Clause: must be greater than 0 and at most 1, received 0",
+		},
+		{
+			name:        "unknown license with suggestion",
+			conf:        scan.Config{Deny: []string{"apache-2.0"}},
+			expectedErr: `deny[0]: unknown license or category "apache-2.0" (did you mean "Apache-2.0"?)`,
+		},
+		{
+			name:        "override without licenses",
+			conf:        scan.Config{Overrides: []scan.Override{{Path: "github.com/foo/bar"}}},
+			expectedErr: "override[0]: at least one license must be listed for github.com/foo/bar",
+		},
+		{
+			name: "invalid version constraint",
+			conf: scan.Config{Exceptions: scan.Exceptions{
+				UnresolvableLicense: []scan.UnresolvableLicense{{Path: "github.com/foo/bar", Version: ">=foo"}},
+			}},
+			expectedErr: "exceptions.unresolvableLicense[0]: invalid version for github.com/foo/bar",
+		},
+		{
+			name: "invalid module rule path",
+			conf: scan.Config{Modules: scan.ModuleRules{
+				Deny: []scan.ModuleRule{{Path: "regex:github.com/(foo"}},
+			}},
+			expectedErr: "modules.deny[0]: invalid path",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.conf.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Order(t *testing.T) {
+	conf := scan.Config{
+		Categories: map[string][]string{"MIT": {"Acme-1.0"}, "Apache-2.0": {"Acme-2.0"}},
+		Thresholds: map[string]float64{"Zlib": 0, "BSD-3-Clause": 0, "MIT": 2},
+		Severity:   scan.Severities{Licenses: map[string]scan.Severity{"zlib": scan.SeverityWarn, "acme-3.0": scan.SeverityWarn}},
+	}
+	expected := []string{
+		"categories.Apache-2.0: category has the same name as a license",
+		"categories.MIT: category has the same name as a license",
+		"thresholds.BSD-3-Clause: must be greater than 0 and at most 1, received 0",
+		"thresholds.MIT: must be greater than 0 and at most 1, received 2",
+		"thresholds.Zlib: must be greater than 0 and at most 1, received 0",
+		`severity.licenses: unknown license or category "acme-3.0" (did you mean "Acme-1.0"?)`,
+		`severity.licenses: unknown license or category "zlib" (did you mean "Zlib"?)`,
+	}
+	// maps are iterated in a random order, so validate repeatedly
+	for i := 0; i < 5; i++ {
+		var merr *multierror.Error
+		require.True(t, errors.As(conf.Validate(), &merr))
+		var actual []string
+		for _, err := range merr.Errors {
+			actual = append(actual, err.Error())
+		}
+		require.Equal(t, expected, actual)
+	}
+}
@@ -0,0 +1,183 @@
+package scan
+
+import (
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+// evaluate inspects each module, checking that (a) license details could be determined, and (b) licenses
+// are permitted by the supplied configuration. Where policies are scoped to particular binaries, each module is
+// evaluated against the policy of every binary that uses it.
+func evaluate(conf Config, policies []policy, binaries []model.BuildInfo, modules []model.Module, overridden map[model.ModuleReference]override) []EvaluatedModule {
+	// build a map each module to binaries that reference them
+	binRefs := make(map[model.ModuleReference][]model.BuildInfo, len(modules))
+	for _, bin := range binaries {
+		for _, ref := range bin.ModuleRefs {
+			binRefs[ref] = append(binRefs[ref], bin)
+		}
+		// main modules are only present if requested
+		if main := bin.MainModule(); main.Path != "" {
+			binRefs[main] = append(binRefs[main], bin)
+		}
+	}
+
+	// check each module
+	thresholds := conf.thresholds()
+	results := make([]EvaluatedModule, 0, len(modules))
+	for _, mod := range modules {
+		bins := binRefs[mod.ModuleReference]
+		var res EvaluatedModule
+		if len(policies) == 1 || len(bins) == 0 {
+			res = evaluateModule(conf, policies[0], mod, overridden)
+		} else {
+			res = evaluateScoped(conf, policies, bins, mod, overridden)
+		}
+		for _, bin := range bins {
+			res.UsedBy = append(res.UsedBy, bin.Path)
+		}
+		if o, found := overridden[mod.ModuleReference]; found {
+			res.Overridden = o.replaced
+		}
+		for _, lic := range mod.BelowThreshold {
+			if resolvedFrom(mod, lic.Path) {
+				// another license was matched with sufficient confidence in the same file
+				continue
+			}
+			res.addNotice(SeverityInfo, "below threshold match for %s (%.2f < %.2f)", lic.Name, lic.Confidence, thresholds.For(lic.Name))
+		}
+		if lc := conf.Severity.LowConfidence; lc != nil {
+			for _, lic := range mod.Licenses {
+				if lic.Confidence < lc.Threshold {
+					res.addNotice(lc.Severity.or(SeverityWarn), "low confidence match for %s (%.2f)", lic.Name, lic.Confidence)
+				}
+			}
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// evaluateScoped evaluates the module against the policy of each supplied binary, recording the decision for each
+// binary and combining them into an overall result
+func evaluateScoped(conf Config, policies []policy, bins []model.BuildInfo, mod model.Module, overridden map[model.ModuleReference]override) EvaluatedModule {
+	// group binaries by policy, evaluating the module once per policy
+	var (
+		selected []policy
+		byPolicy = make(map[string][]string)
+	)
+	for _, bin := range bins {
+		p := policyFor(policies, bin)
+		if _, found := byPolicy[p.name]; !found {
+			selected = append(selected, p)
+		}
+		byPolicy[p.name] = append(byPolicy[p.name], bin.Path)
+	}
+
+	res := EvaluatedModule{
+		Module:   mod,
+		Decision: DecisionAllowed,
+	}
+	for _, p := range selected {
+		scoped := evaluateModule(conf, p, mod, overridden)
+		for _, path := range byPolicy[p.name] {
+			res.Binaries = append(res.Binaries, BinaryDecision{
+				Binary:       path,
+				Policy:       p.name,
+				Decision:     scoped.Decision,
+				NotPermitted: scoped.NotPermitted,
+				Severity:     scoped.Severity,
+			})
+		}
+		res.combine(scoped)
+	}
+	return res
+}
+
+// evaluateModule evaluates the module against a single policy
+func evaluateModule(conf Config, p policy, mod model.Module, overridden map[model.ModuleReference]override) EvaluatedModule {
+	res := EvaluatedModule{
+		Module:   mod,
+		Decision: DecisionAllowed,
+	}
+	if o, found := overridden[mod.ModuleReference]; found {
+		res.addRule(o.Rule)
+	}
+	if m, found := p.rules.mostSpecific(p.rules.modules, mod, nil); found {
+		// module rules take precedence over license checks
+		res.addRule(m.Rule)
+		if m.Type == RuleTypeModuleDeny {
+			res.Decision = DecisionNotAllowedModuleDenied
+			res.Severity = conf.Severity.ModuleDenied.or(SeverityError)
+		} else {
+			res.Decision = DecisionAllowedModuleApproved
+		}
+		res.addExpiryNotices(conf, p.rules)
+		return res
+	}
+	if len(mod.Licenses) == 0 {
+		decision := unresolvableDecision(mod)
+		if ex, found := p.rules.mostSpecific(p.rules.unresolvable(decision), mod, nil); found {
+			res.addRule(ex.Rule)
+		} else {
+			res.Decision = decision
+			res.Severity = conf.Severity.UnresolvableLicense.or(SeverityError)
+		}
+	}
+	for _, lic := range mod.Licenses {
+		if p.licenses.permitted(lic.Name) {
+			continue
+		}
+		if ex, found := p.rules.mostSpecific(p.rules.licenseNotPermitted, mod, coversLicense(lic.Name)); found {
+			res.addRule(ex.Rule)
+			continue
+		}
+		res.Decision = DecisionNotAllowedLicenseNotPermitted
+		res.NotPermitted = append(res.NotPermitted, lic.Name)
+		res.Severity = maxSeverity(res.Severity, p.licenses.violationSeverity(lic.Name))
+	}
+	res.addExpiryNotices(conf, p.rules)
+	return res
+}
+
+// unresolvableDecision returns the decision for a module without resolved licenses, based on why they could not be
+// resolved
+func unresolvableDecision(mod model.Module) Decision {
+	switch {
+	case mod.Dir == "":
+		return DecisionNotAllowedModuleUnavailable
+	case len(mod.LicenseFiles) == 0:
+		return DecisionNotAllowedNoLicenseFiles
+	default:
+		return DecisionNotAllowedUnclassifiedLicense
+	}
+}
+
+// addExpiryNotices warns of expired rules matching the module, and applied rules that are about to expire
+func (r *EvaluatedModule) addExpiryNotices(conf Config, rules rules) {
+	for _, e := range rules.all() {
+		if e.matches(r.Module) && e.expired(rules.now) {
+			r.addNotice(SeverityWarn, "%s for %s expired on %s and no longer applies", e.Type, e.Path, e.Expires)
+		}
+	}
+	for _, rule := range r.Rules {
+		if rule.expiresWithin(rules.now, conf.ExpiryWarningDays) {
+			r.addNotice(SeverityWarn, "%s for %s expires on %s", rule.Type, rule.Path, rule.Expires)
+		}
+	}
+}
+
+// coversLicense returns a predicate for selecting exceptions that cover the named license
+func coversLicense(name string) func(entry) bool {
+	return func(e entry) bool {
+		return e.coversLicense(name)
+	}
+}
+
+// resolvedFrom returns true if any of the module's resolved licenses were matched in the file at the supplied path
+func resolvedFrom(mod model.Module, path string) bool {
+	for _, lic := range mod.Licenses {
+		if lic.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,790 @@
+package scan_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// newModule returns a module with a license file for each of the supplied licenses
+func newModule(path, version string, licenses ...string) model.Module {
+	mod := model.Module{
+		ModuleReference: model.ModuleReference{Path: path, Version: version},
+		Dir:             "/mod/" + path + "@" + version,
+	}
+	for _, lic := range licenses {
+		file := mod.Dir + "/LICENSE." + lic
+		mod.Licenses = append(mod.Licenses, model.License{Name: lic, Path: file, Confidence: 1})
+		mod.LicenseFiles = append(mod.LicenseFiles, file)
+	}
+	return mod
+}
+
+// evaluate evaluates the modules, as used by a single binary
+func evaluate(t *testing.T, conf scan.Config, modules ...model.Module) scan.Summary {
+	bin := model.BuildInfo{Path: "/bin/app", PackagePath: "github.com/app/cmd/app", ModulePath: "github.com/app"}
+	for _, mod := range modules {
+		bin.ModuleRefs = append(bin.ModuleRefs, mod.ModuleReference)
+	}
+	summary, err := scan.Evaluate(conf, []model.BuildInfo{bin}, modules)
+	require.NoError(t, err)
+	return summary
+}
+
+func TestEvaluate_Licenses(t *testing.T) {
+	testCases := []struct {
+		name         string
+		conf         scan.Config
+		licenses     []string
+		decision     scan.Decision
+		notPermitted []string
+	}{
+		{
+			name:     "no allow list",
+			licenses: []string{"GPL-3.0"},
+			decision: scan.DecisionAllowed,
+		},
+		{
+			name:     "allowed by name",
+			conf:     scan.Config{Allow: []string{"MIT"}},
+			licenses: []string{"MIT"},
+			decision: scan.DecisionAllowed,
+		},
+		{
+			name:         "not in allow list",
+			conf:         scan.Config{Allow: []string{"MIT"}},
+			licenses:     []string{"MIT", "Apache-2.0"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"Apache-2.0"},
+		},
+		{
+			name:         "denied without allow list",
+			conf:         scan.Config{Deny: []string{"GPL-3.0"}},
+			licenses:     []string{"GPL-3.0"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"GPL-3.0"},
+		},
+		{
+			name:     "allowed by built-in category",
+			conf:     scan.Config{Allow: []string{"permissive"}},
+			licenses: []string{"Apache-2.0", "BSD-3-Clause"},
+			decision: scan.DecisionAllowed,
+		},
+		{
+			name:         "outside allowed category",
+			conf:         scan.Config{Allow: []string{"permissive"}},
+			licenses:     []string{"GPL-3.0"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"GPL-3.0"},
+		},
+		{
+			name:         "denied by name within allowed category",
+			conf:         scan.Config{Allow: []string{"permissive"}, Deny: []string{"MIT"}},
+			licenses:     []string{"MIT"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"MIT"},
+		},
+		{
+			name:     "allowed by name within denied category",
+			conf:     scan.Config{Allow: []string{"LGPL-3.0"}, Deny: []string{"weak-copyleft"}},
+			licenses: []string{"LGPL-3.0"},
+			decision: scan.DecisionAllowed,
+		},
+		{
+			name:         "category both allowed and denied",
+			conf:         scan.Config{Allow: []string{"permissive"}, Deny: []string{"permissive"}},
+			licenses:     []string{"MIT"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"MIT"},
+		},
+		{
+			name: "configured category",
+			conf: scan.Config{
+				Allow:      []string{"vetted"},
+				Categories: map[string][]string{"vetted": {"GPL-3.0"}},
+			},
+			licenses: []string{"GPL-3.0"},
+			decision: scan.DecisionAllowed,
+		},
+		{
+			name: "configured category replaces built-in",
+			conf: scan.Config{
+				Allow:      []string{"permissive"},
+				Categories: map[string][]string{"vetted": {"MIT"}},
+			},
+			licenses:     []string{"MIT"},
+			decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+			notPermitted: []string{"MIT"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, tc.conf, newModule("github.com/foo/bar", "v1.0.0", tc.licenses...))
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.decision, m.Decision)
+			assert.Equal(t, tc.notPermitted, m.NotPermitted)
+			assert.Equal(t, []string{"/bin/app"}, m.UsedBy)
+		})
+	}
+}
+
+func TestEvaluate_Severity(t *testing.T) {
+	testCases := []struct {
+		name        string
+		conf        scan.Config
+		module      model.Module
+		severity    scan.Severity
+		failed      bool
+		explanation string
+	}{
+		{
+			name:        "allowed",
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			explanation: "allowed",
+		},
+		{
+			name:        "license not permitted defaults to error",
+			conf:        scan.Config{Allow: []string{"MIT"}},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"),
+			severity:    scan.SeverityError,
+			failed:      true,
+			explanation: "not allowed - non-permitted licenses: [GPL-3.0]",
+		},
+		{
+			name: "configured license not permitted severity",
+			conf: scan.Config{
+				Allow:    []string{"MIT"},
+				Severity: scan.Severities{LicenseNotPermitted: scan.SeverityWarn},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"),
+			severity:    scan.SeverityWarn,
+			explanation: "not allowed - non-permitted licenses: [GPL-3.0]",
+		},
+		{
+			name: "license severity takes precedence over category",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Severity: scan.Severities{Licenses: map[string]scan.Severity{
+					"GPL-3.0":         scan.SeverityInfo,
+					"strong-copyleft": scan.SeverityError,
+				}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"),
+			severity:    scan.SeverityInfo,
+			explanation: "not allowed - non-permitted licenses: [GPL-3.0]",
+		},
+		{
+			name: "category severity",
+			conf: scan.Config{
+				Allow:    []string{"MIT"},
+				Severity: scan.Severities{Licenses: map[string]scan.Severity{"strong-copyleft": scan.SeverityWarn}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"),
+			severity:    scan.SeverityWarn,
+			explanation: "not allowed - non-permitted licenses: [GPL-3.0]",
+		},
+		{
+			name: "highest severity of several licenses",
+			conf: scan.Config{
+				Allow:    []string{"MIT"},
+				Severity: scan.Severities{Licenses: map[string]scan.Severity{"GPL-3.0": scan.SeverityInfo}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0", "AGPL-3.0"),
+			severity:    scan.SeverityError,
+			failed:      true,
+			explanation: "not allowed - non-permitted licenses: [GPL-3.0 AGPL-3.0]",
+		},
+		{
+			name:        "configured unresolvable license severity",
+			conf:        scan.Config{Severity: scan.Severities{UnresolvableLicense: scan.SeverityWarn}},
+			module:      newModule("github.com/foo/bar", "v1.0.0"),
+			severity:    scan.SeverityWarn,
+			explanation: "not allowed - no license files found",
+		},
+		{
+			name: "allowed with error notice",
+			conf: scan.Config{Severity: scan.Severities{
+				LowConfidence: &scan.LowConfidence{Threshold: 1.1, Severity: scan.SeverityError},
+			}},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			severity:    scan.SeverityError,
+			failed:      true,
+			explanation: "allowed; low confidence match for MIT (1.00)",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, tc.conf, tc.module)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.severity, m.Severity)
+			assert.Equal(t, tc.failed, m.Failed())
+			assert.Equal(t, tc.explanation, m.ExplainFailure())
+
+			m.Baselined = true
+			assert.False(t, m.Failed())
+		})
+	}
+}
+
+func TestEvaluate_MostSpecificRule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides []scan.Override
+		expected  string // path of the override applied
+	}{
+		{
+			name: "exact path beats glob",
+			overrides: []scan.Override{
+				{Path: "github.com/foo/*", Licenses: []string{"MIT"}},
+				{Path: "github.com/foo/bar", Licenses: []string{"MIT"}},
+			},
+			expected: "github.com/foo/bar",
+		},
+		{
+			name: "glob with more literal characters wins",
+			overrides: []scan.Override{
+				{Path: "github.com/**", Licenses: []string{"MIT"}},
+				{Path: "github.com/foo/*", Licenses: []string{"MIT"}},
+			},
+			expected: "github.com/foo/*",
+		},
+		{
+			name: "glob beats regex",
+			overrides: []scan.Override{
+				{Path: `regex:github\.com/foo/bar`, Licenses: []string{"MIT"}},
+				{Path: "github.com/**", Licenses: []string{"MIT"}},
+			},
+			expected: "github.com/**",
+		},
+		{
+			name: "first configured wins ties",
+			overrides: []scan.Override{
+				{Path: "github.com/*/bar", Licenses: []string{"MIT"}},
+				{Path: "github.com/foo/*", Licenses: []string{"MIT"}},
+			},
+			expected: "github.com/*/bar",
+		},
+		{
+			name: "non-matching entries are ignored",
+			overrides: []scan.Override{
+				{Path: "github.com/foo/baz", Licenses: []string{"MIT"}},
+				{Path: `regex:github\.com/.*`, Licenses: []string{"MIT"}},
+			},
+			expected: `regex:github\.com/.*`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, scan.Config{Overrides: tc.overrides}, newModule("github.com/foo/bar", "v1.0.0"))
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			require.Len(t, m.Rules, 1)
+			assert.Equal(t, scan.RuleTypeOverride, m.Rules[0].Type)
+			assert.Equal(t, tc.expected, m.Rules[0].Path)
+			assert.Equal(t, scan.DecisionAllowed, m.Decision)
+		})
+	}
+}
+
+func TestEvaluate_Overridden(t *testing.T) {
+	detected := newModule("github.com/foo/bar", "v1.0.0", "Apache-2.0")
+	detected.Licenses[0].Content = "Apache License"
+
+	testCases := []struct {
+		name     string
+		module   model.Module
+		expected []model.License
+	}{
+		{
+			name:     "licenses detected in license files are retained",
+			module:   detected,
+			expected: detected.Licenses,
+		},
+		{
+			name:   "no license files",
+			module: newModule("github.com/foo/bar", "v1.0.0"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, scan.Config{Overrides: []scan.Override{{Path: "github.com/foo/bar", Licenses: []string{"MIT"}}}}, tc.module)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, []model.License{{Name: "MIT", Confidence: 1}}, m.Licenses)
+			assert.Equal(t, tc.expected, m.Overridden)
+		})
+	}
+}
+
+// date returns the date the supplied number of days from today
+func date(t *testing.T, days int) *scan.Date {
+	var d scan.Date
+	require.NoError(t, d.UnmarshalText([]byte(time.Now().UTC().AddDate(0, 0, days).Format("2006-01-02"))))
+	return &d
+}
+
+func TestEvaluate_Expiry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expires  int // days from today
+		decision scan.Decision
+		notice   string
+	}{
+		{
+			name:     "expired",
+			expires:  -1,
+			decision: scan.DecisionNotAllowedLicenseNotPermitted,
+			notice:   "licenseNotPermitted for github.com/foo/bar expired on %s and no longer applies",
+		},
+		{
+			name:     "expires today",
+			expires:  0,
+			decision: scan.DecisionAllowed,
+			notice:   "licenseNotPermitted for github.com/foo/bar expires on %s",
+		},
+		{
+			name:     "expires within warning period",
+			expires:  5,
+			decision: scan.DecisionAllowed,
+			notice:   "licenseNotPermitted for github.com/foo/bar expires on %s",
+		},
+		{
+			name:     "expires after warning period",
+			expires:  30,
+			decision: scan.DecisionAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expires := date(t, tc.expires)
+			conf := scan.Config{
+				Allow:             []string{"MIT"},
+				ExpiryWarningDays: 7,
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar", Metadata: scan.Metadata{Expires: expires}},
+				}},
+			}
+			summary := evaluate(t, conf, newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"))
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.decision, m.Decision)
+			if tc.notice == "" {
+				assert.Empty(t, m.Notices)
+				return
+			}
+			assert.Equal(t, []scan.Notice{{Severity: scan.SeverityWarn, Message: fmt.Sprintf(tc.notice, expires)}}, m.Notices)
+		})
+	}
+}
+
+func TestEvaluate_RequireMetadata(t *testing.T) {
+	conf := scan.Config{
+		RequireMetadata: []string{"justification", "owner"},
+		Overrides: []scan.Override{
+			{Path: "github.com/foo/bar", Licenses: []string{"MIT"}, Metadata: scan.Metadata{Owner: "team"}},
+		},
+	}
+	_, err := scan.Evaluate(conf, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "override[0]: override for github.com/foo/bar is missing required metadata: justification")
+}
+
+func TestEvaluate_StaleRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		conf     scan.Config
+		expected []scan.StaleRule
+	}{
+		{
+			name: "applied",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar"},
+				}},
+			},
+		},
+		{
+			name: "matched no module",
+			conf: scan.Config{Overrides: []scan.Override{{Path: "github.com/foo/baz", Licenses: []string{"MIT"}}}},
+			expected: []scan.StaleRule{{
+				Rule:     scan.Rule{Type: scan.RuleTypeOverride, Path: "github.com/foo/baz", Licenses: []string{"MIT"}},
+				Reason:   "matched no scanned module",
+				Severity: scan.SeverityWarn,
+			}},
+		},
+		{
+			name: "matched no version",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar", Version: "v2.0.0"},
+				}},
+			},
+			expected: []scan.StaleRule{{
+				Rule:     scan.Rule{Type: scan.RuleTypeLicenseNotPermitted, Path: "github.com/foo/bar", Version: "v2.0.0"},
+				Reason:   `version "v2.0.0" matched none of the scanned versions (github.com/foo/bar@v1.0.0)`,
+				Severity: scan.SeverityWarn,
+			}},
+		},
+		{
+			name: "not required",
+			conf: scan.Config{
+				Allow: []string{"GPL-3.0", "AGPL-3.0"},
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar"},
+				}},
+				Severity: scan.Severities{StaleRules: scan.SeverityError},
+			},
+			expected: []scan.StaleRule{{
+				Rule:     scan.Rule{Type: scan.RuleTypeLicenseNotPermitted, Path: "github.com/foo/bar"},
+				Reason:   "not required by any scanned module",
+				Severity: scan.SeverityError,
+			}},
+		},
+		{
+			name: "override of detected licenses",
+			conf: scan.Config{Overrides: []scan.Override{{Path: "github.com/foo/bar", Licenses: []string{"AGPL-3.0", "GPL-3.0"}}}},
+			expected: []scan.StaleRule{{
+				Rule:     scan.Rule{Type: scan.RuleTypeOverride, Path: "github.com/foo/bar", Licenses: []string{"AGPL-3.0", "GPL-3.0"}},
+				Reason:   "licenses are already detected as AGPL-3.0, GPL-3.0",
+				Severity: scan.SeverityWarn,
+			}},
+		},
+		{
+			name: "exceptions differing only by license",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar", Licenses: []string{"GPL-3.0"}},
+					{Path: "github.com/foo/bar", Licenses: []string{"AGPL-3.0"}},
+				}},
+			},
+		},
+		{
+			name: "exceptions differing only by license, one not required",
+			conf: scan.Config{
+				Allow: []string{"MIT", "AGPL-3.0"},
+				Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+					{Path: "github.com/foo/bar", Licenses: []string{"GPL-3.0"}},
+					{Path: "github.com/foo/bar", Licenses: []string{"AGPL-3.0"}},
+				}},
+			},
+			expected: []scan.StaleRule{{
+				Rule:     scan.Rule{Type: scan.RuleTypeLicenseNotPermitted, Path: "github.com/foo/bar", Licenses: []string{"AGPL-3.0"}},
+				Reason:   "not required by any scanned module",
+				Severity: scan.SeverityWarn,
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, tc.conf, newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0", "AGPL-3.0"))
+			assert.Equal(t, tc.expected, summary.StaleRules)
+		})
+	}
+}
+
+func TestEvaluate_ExpiredRulesAreNotStale(t *testing.T) {
+	conf := scan.Config{
+		Overrides: []scan.Override{
+			{Path: "github.com/foo/bar", Licenses: []string{"MIT"}, Metadata: scan.Metadata{Expires: date(t, -1)}},
+		},
+	}
+	summary := evaluate(t, conf, newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"))
+	assert.Empty(t, summary.StaleRules)
+	require.Len(t, summary.Modules, 1)
+	assert.Empty(t, summary.Modules[0].Rules)
+	assert.Len(t, summary.Modules[0].Notices, 1)
+}
+
+func TestEvaluate_Policies(t *testing.T) {
+	tool := model.BuildInfo{Path: "/bin/tool", PackagePath: "github.com/app/cmd/tool", ModulePath: "github.com/app"}
+	server := model.BuildInfo{Path: "/srv/server", PackagePath: "github.com/app/cmd/server", ModulePath: "github.com/app"}
+
+	testCases := []struct {
+		name     string
+		conf     scan.Config
+		decision scan.Decision
+		binaries []scan.BinaryDecision
+	}{
+		{
+			name:     "no policies",
+			conf:     scan.Config{Allow: []string{"MIT"}},
+			decision: scan.DecisionNotAllowedLicenseNotPermitted,
+		},
+		{
+			name: "scoped allow list",
+			conf: scan.Config{
+				Allow:    []string{"MIT"},
+				Policies: []scan.Policy{{Name: "tools", Binaries: []string{"github.com/app/cmd/tool"}, Allow: []string{"GPL-3.0"}}},
+			},
+			decision: scan.DecisionNotAllowedLicenseNotPermitted,
+			binaries: []scan.BinaryDecision{
+				{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+				{Binary: "/srv/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+			},
+		},
+		{
+			name: "scoped deny list",
+			conf: scan.Config{
+				Policies: []scan.Policy{{Name: "servers", Binaries: []string{"/srv/*"}, Deny: []string{"strong-copyleft"}}},
+			},
+			decision: scan.DecisionNotAllowedLicenseNotPermitted,
+			binaries: []scan.BinaryDecision{
+				{Binary: "/bin/tool", Decision: scan.DecisionAllowed},
+				{Binary: "/srv/server", Policy: "servers", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+			},
+		},
+		{
+			name: "first selecting policy wins",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Policies: []scan.Policy{
+					{Name: "all", Binaries: []string{"github.com/app"}, Allow: []string{"GPL-3.0"}},
+					{Name: "servers", Binaries: []string{"/srv/**"}, Allow: []string{"MIT"}},
+				},
+			},
+			decision: scan.DecisionAllowed,
+			binaries: []scan.BinaryDecision{
+				{Binary: "/bin/tool", Policy: "all", Decision: scan.DecisionAllowed},
+				{Binary: "/srv/server", Policy: "all", Decision: scan.DecisionAllowed},
+			},
+		},
+		{
+			name: "scoped exceptions",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Policies: []scan.Policy{{
+					Name:     "tools",
+					Binaries: []string{"/bin/tool"},
+					Exceptions: scan.Exceptions{LicenseNotPermitted: []scan.LicenseNotPermitted{
+						{Path: "github.com/foo/bar"},
+					}},
+				}},
+			},
+			decision: scan.DecisionNotAllowedLicenseNotPermitted,
+			binaries: []scan.BinaryDecision{
+				{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+				{Binary: "/srv/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mod := newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0")
+			tool.ModuleRefs = []model.ModuleReference{mod.ModuleReference}
+			server.ModuleRefs = []model.ModuleReference{mod.ModuleReference}
+			summary, err := scan.Evaluate(tc.conf, []model.BuildInfo{tool, server}, []model.Module{mod})
+			require.NoError(t, err)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.decision, m.Decision)
+			assert.Equal(t, tc.binaries, m.Binaries)
+			assert.Equal(t, []string{"/bin/tool", "/srv/server"}, m.UsedBy)
+		})
+	}
+}
+
+func TestEvaluate_InvalidPolicies(t *testing.T) {
+	conf := scan.Config{
+		Policies: []scan.Policy{
+			{Binaries: []string{"/bin/*"}},
+			{Name: "tools", Binaries: []string{"/bin/*"}},
+			{Name: "tools"},
+		},
+	}
+	_, err := scan.Evaluate(conf, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policies[0]: name must be specified")
+	assert.Contains(t, err.Error(), `policies[2]: duplicate policy name "tools"`)
+	assert.Contains(t, err.Error(), "policies[2]: at least one binary pattern must be specified")
+}
+
+func TestEvaluate_ModuleRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		conf        scan.Config
+		module      model.Module
+		decision    scan.Decision
+		severity    scan.Severity
+		explanation string
+	}{
+		{
+			name: "approved despite license",
+			conf: scan.Config{
+				Allow: []string{"MIT"},
+				Modules: scan.ModuleRules{Allow: []scan.ModuleRule{
+					{Path: "github.com/foo/bar", Metadata: scan.Metadata{Justification: "commercial agreement"}},
+				}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "GPL-3.0"),
+			decision:    scan.DecisionAllowedModuleApproved,
+			explanation: "allowed - module approved by rule for github.com/foo/bar (commercial agreement)",
+		},
+		{
+			name: "approved without licenses",
+			conf: scan.Config{
+				Modules: scan.ModuleRules{Allow: []scan.ModuleRule{{Path: "github.com/foo/*"}}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0"),
+			decision:    scan.DecisionAllowedModuleApproved,
+			explanation: "allowed - module approved by rule for github.com/foo/*",
+		},
+		{
+			name: "denied despite license",
+			conf: scan.Config{
+				Modules: scan.ModuleRules{Deny: []scan.ModuleRule{{Path: "github.com/foo/bar"}}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			decision:    scan.DecisionNotAllowedModuleDenied,
+			severity:    scan.SeverityError,
+			explanation: "not allowed - module denied by rule for github.com/foo/bar",
+		},
+		{
+			name: "configured denied severity",
+			conf: scan.Config{
+				Modules:  scan.ModuleRules{Deny: []scan.ModuleRule{{Path: "github.com/foo/bar"}}},
+				Severity: scan.Severities{ModuleDenied: scan.SeverityWarn},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			decision:    scan.DecisionNotAllowedModuleDenied,
+			severity:    scan.SeverityWarn,
+			explanation: "not allowed - module denied by rule for github.com/foo/bar",
+		},
+		{
+			name: "denied version only",
+			conf: scan.Config{
+				Modules: scan.ModuleRules{Deny: []scan.ModuleRule{{Path: "github.com/foo/bar", Version: ">=v1.1.0"}}},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			decision:    scan.DecisionAllowed,
+			explanation: "allowed",
+		},
+		{
+			name: "more specific allow beats deny",
+			conf: scan.Config{
+				Modules: scan.ModuleRules{
+					Allow: []scan.ModuleRule{{Path: "github.com/foo/bar"}},
+					Deny:  []scan.ModuleRule{{Path: "github.com/foo/**"}},
+				},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			decision:    scan.DecisionAllowedModuleApproved,
+			explanation: "allowed - module approved by rule for github.com/foo/bar",
+		},
+		{
+			name: "deny wins ties",
+			conf: scan.Config{
+				Modules: scan.ModuleRules{
+					Allow: []scan.ModuleRule{{Path: "github.com/foo/bar"}},
+					Deny:  []scan.ModuleRule{{Path: "github.com/foo/bar"}},
+				},
+			},
+			module:      newModule("github.com/foo/bar", "v1.0.0", "MIT"),
+			decision:    scan.DecisionNotAllowedModuleDenied,
+			severity:    scan.SeverityError,
+			explanation: "not allowed - module denied by rule for github.com/foo/bar",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, tc.conf, tc.module)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.decision, m.Decision)
+			assert.Equal(t, tc.severity, m.Severity)
+			assert.Equal(t, tc.explanation, m.ExplainDecision())
+		})
+	}
+}
+
+func TestEvaluate_Unresolvable(t *testing.T) {
+	unavailable := model.Module{ModuleReference: model.ModuleReference{Path: "github.com/foo/bar", Version: "v1.0.0"}}
+	unavailableReason := unavailable
+	unavailableReason.Unavailable = "github.com/foo/bar@v1.0.0: reading https://proxy.golang.org/github.com/foo/bar/@v/v1.0.0.zip: 404 Not Found"
+	noFiles := newModule("github.com/foo/bar", "v1.0.0")
+	unclassified := newModule("github.com/foo/bar", "v1.0.0")
+	unclassified.LicenseFiles = []string{unclassified.Dir + "/LICENSE"}
+	unclassified.BelowThreshold = []model.License{{Name: "MIT", Path: unclassified.Dir + "/LICENSE", Confidence: 0.6}}
+
+	testCases := []struct {
+		name        string
+		exceptions  scan.Exceptions
+		module      model.Module
+		decision    scan.Decision
+		explanation string
+	}{
+		{
+			name:        "module unavailable",
+			module:      unavailable,
+			decision:    scan.DecisionNotAllowedModuleUnavailable,
+			explanation: "not allowed - module source unavailable",
+		},
+		{
+			name:        "module unavailable, with the reason",
+			module:      unavailableReason,
+			decision:    scan.DecisionNotAllowedModuleUnavailable,
+			explanation: "not allowed - module source unavailable (github.com/foo/bar@v1.0.0: reading https://proxy.golang.org/github.com/foo/bar/@v/v1.0.0.zip: 404 Not Found)",
+		},
+		{
+			name:        "no license files",
+			module:      noFiles,
+			decision:    scan.DecisionNotAllowedNoLicenseFiles,
+			explanation: "not allowed - no license files found",
+		},
+		{
+			name:        "unclassified license",
+			module:      unclassified,
+			decision:    scan.DecisionNotAllowedUnclassifiedLicense,
+			explanation: "not allowed - license files could not be classified (best guess: MIT, confidence 0.60)",
+		},
+		{
+			name:        "specific exception",
+			exceptions:  scan.Exceptions{NoLicenseFiles: []scan.UnresolvableLicense{{Path: "github.com/foo/bar"}}},
+			module:      noFiles,
+			decision:    scan.DecisionAllowed,
+			explanation: "allowed",
+		},
+		{
+			name:        "exception for another reason",
+			exceptions:  scan.Exceptions{NoLicenseFiles: []scan.UnresolvableLicense{{Path: "github.com/foo/bar"}}},
+			module:      unclassified,
+			decision:    scan.DecisionNotAllowedUnclassifiedLicense,
+			explanation: "not allowed - license files could not be classified (best guess: MIT, confidence 0.60)",
+		},
+		{
+			name:        "general exception",
+			exceptions:  scan.Exceptions{UnresolvableLicense: []scan.UnresolvableLicense{{Path: "github.com/foo/bar"}}},
+			module:      unavailable,
+			decision:    scan.DecisionAllowed,
+			explanation: "allowed",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := evaluate(t, scan.Config{Exceptions: tc.exceptions}, tc.module)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, tc.decision, m.Decision)
+			assert.Equal(t, tc.explanation, m.ExplainDecision())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/module"
+	"golang.org/x/mod/modfile"
+)
+
+// develVersion is the version reported for main modules built from a source checkout without version control
+// information
+const develVersion = "(devel)"
+
+// mainModules returns the main modules of the supplied binaries. Main modules are located in whichever of the source
+// directories has a go.mod declaring the same module path. Otherwise, versioned main modules are fetched from the
+// module cache, while those built from a source checkout (without a version, or with uncommitted changes) are
+// returned without a directory, and are evaluated as unavailable.
+func mainModules(ctx context.Context, binaries []model.BuildInfo, sourceDirs []string) ([]model.Module, error) {
+	sources := make(map[string]string, len(sourceDirs))
+	for _, dir := range sourceDirs {
+		path, err := modulePath(dir)
+		if err != nil {
+			return nil, err
+		}
+		sources[path] = dir
+	}
+
+	var (
+		modules []model.Module
+		fetch   []model.ModuleReference
+		seen    = make(map[model.ModuleReference]bool)
+	)
+	for _, bin := range binaries {
+		ref := bin.MainModule()
+		if ref.Path == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		dir, found := sources[ref.Path]
+		switch {
+		case found:
+			modules = append(modules, model.Module{ModuleReference: ref, Dir: dir})
+		case fetchable(ref.Version):
+			fetch = append(fetch, ref)
+		default:
+			modules = append(modules, model.Module{
+				ModuleReference: ref,
+				Unavailable:     "built from a source checkout, and no source directory was supplied via --source",
+			})
+		}
+	}
+	if len(fetch) > 0 {
+		fetched, err := module.Fetch(ctx, fetch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch main modules (hint: supply their source directories instead): %w", err)
+		}
+		modules = append(modules, fetched...)
+	}
+	for i := range modules {
+		modules[i].Main = true
+	}
+	return modules, nil
+}
+
+// fetchable returns true if the main module version can be fetched from the module cache
+func fetchable(version string) bool {
+	return version != "" && version != develVersion && !strings.HasSuffix(version, "+dirty")
+}
+
+// modulePath returns the module path declared by the go.mod in the supplied directory
+func modulePath(dir string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod of source directory: %w", err)
+	}
+	path := modfile.ModulePath(b)
+	if path == "" {
+		return "", fmt.Errorf("no module path declared in %s", filepath.Join(dir, "go.mod"))
+	}
+	return path, nil
+}
@@ -0,0 +1,158 @@
+package scan
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/uw-labs/lichen/internal/license"
+	"github.com/uw-labs/lichen/internal/match"
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+// policy is the compiled form of either the top-level config, or a policy scoped to particular binaries
+type policy struct {
+	name     string
+	binaries []match.Path
+	licenses licensePolicy
+	rules    rules
+}
+
+// compilePolicies compiles the top-level config followed by each scoped policy, returning all problems encountered.
+// The first policy returned is always the top-level config.
+func compilePolicies(conf Config) ([]policy, error) {
+	rules, err := compileRules(conf)
+	policies := []policy{{
+		licenses: newLicensePolicy(conf),
+		rules:    rules,
+	}}
+	names := make(map[string]bool, len(conf.Policies))
+	for i, p := range conf.Policies {
+		field := fmt.Sprintf("policies[%d]", i)
+		if p.Name == "" {
+			err = multierror.Append(err, fmt.Errorf("%s: name must be specified", field))
+		} else if names[p.Name] {
+			err = multierror.Append(err, fmt.Errorf("%s: duplicate policy name %q", field, p.Name))
+		}
+		names[p.Name] = true
+		if len(p.Binaries) == 0 {
+			err = multierror.Append(err, fmt.Errorf("%s: at least one binary pattern must be specified", field))
+		}
+		compiled := policy{
+			name:     p.Name,
+			licenses: newLicensePolicy(conf.scoped(p)),
+		}
+		for j, pattern := range p.Binaries {
+			path, pErr := match.ParsePath(pattern)
+			if pErr != nil {
+				err = multierror.Append(err, fmt.Errorf("%s.binaries[%d]: %w", field, j, pErr))
+			}
+			compiled.binaries = append(compiled.binaries, path)
+		}
+		var rErr error
+		compiled.rules, rErr = rules.scoped(p, field+".exceptions", conf.RequireMetadata)
+		if rErr != nil {
+			err = multierror.Append(err, rErr)
+		}
+		policies = append(policies, compiled)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// selects returns true if the policy applies to the supplied binary, by matching its OS path, package path or
+// module path. The top-level policy selects no binaries.
+func (p policy) selects(bin model.BuildInfo) bool {
+	for _, path := range p.binaries {
+		if path.Match(filepath.ToSlash(bin.Path)) || path.Match(bin.PackagePath) || path.Match(bin.ModulePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyFor returns the first scoped policy that selects the binary, falling back to the top-level policy
+func policyFor(policies []policy, bin model.BuildInfo) policy {
+	for _, p := range policies[1:] {
+		if p.selects(bin) {
+			return p
+		}
+	}
+	return policies[0]
+}
+
+// licensePolicy determines whether licenses are permitted, based on the configured allow and deny lists. Entries in
+// either list may name a license or a category of licenses.
+type licensePolicy struct {
+	allow      map[string]bool
+	deny       map[string]bool
+	categories map[string]string
+	severities map[string]Severity
+	severity   Severity
+}
+
+func newLicensePolicy(conf Config) licensePolicy {
+	p := licensePolicy{
+		allow:      make(map[string]bool, len(conf.Allow)),
+		deny:       make(map[string]bool, len(conf.Deny)),
+		categories: make(map[string]string),
+		severities: conf.Severity.Licenses,
+		severity:   conf.Severity.LicenseNotPermitted.or(SeverityError),
+	}
+	for _, lic := range conf.Allow {
+		p.allow[lic] = true
+	}
+	for _, lic := range conf.Deny {
+		p.deny[lic] = true
+	}
+	// configured categories take precedence over the built-in mapping
+	for category, licenses := range conf.Categories {
+		for _, lic := range licenses {
+			p.categories[lic] = category
+		}
+	}
+	return p
+}
+
+// category returns the category of the named license
+func (p licensePolicy) category(name string) string {
+	if category, found := p.categories[name]; found {
+		return category
+	}
+	return string(license.CategoryOf(name))
+}
+
+// permitted returns true if the named license is permitted. Entries naming the license itself take precedence over
+// entries naming its category, and denials take precedence over allowances. If no licenses are explicitly allowed,
+// all licenses other than those denied are permitted.
+func (p licensePolicy) permitted(name string) bool {
+	if p.deny[name] {
+		return false
+	}
+	if p.allow[name] {
+		return true
+	}
+	if category := p.category(name); category != "" {
+		if p.deny[category] {
+			return false
+		}
+		if p.allow[category] {
+			return true
+		}
+	}
+	return len(p.allow) == 0
+}
+
+// violationSeverity returns the severity of the named license not being permitted. Severities configured for the
+// license itself take precedence over those configured for its category.
+func (p licensePolicy) violationSeverity(name string) Severity {
+	if s, found := p.severities[name]; found {
+		return s
+	}
+	if s, found := p.severities[p.category(name)]; found {
+		return s
+	}
+	return p.severity
+}
@@ -7,19 +7,136 @@ import (
 )
 
 type Summary struct {
-	Modules  []EvaluatedModule
-	Binaries []model.BuildInfo
+	Modules     []EvaluatedModule
+	Binaries    []model.BuildInfo
+	StaleRules  []StaleRule         `json:",omitempty"` // overrides and exceptions that did not apply to any module
+	Comparisons []LicenseComparison `json:",omitempty"` // licenses declared by scanned SBOMs compared with those detected
 }
 
 type EvaluatedModule struct {
 	model.Module
 	Decision     Decision
-	NotPermitted []string `json:",omitempty"`
+	NotPermitted []string         `json:",omitempty"`
+	Severity     Severity         `json:",omitempty"` // the highest severity of the decision and notices
+	Notices      []Notice         `json:",omitempty"`
+	Rules        []Rule           `json:",omitempty"` // overrides and exceptions applied to the module
+	Overridden   []model.License  `json:",omitempty"` // licenses detected in the module's license files, replaced by an override
+	Binaries     []BinaryDecision `json:",omitempty"` // per binary decisions, when policies are scoped to binaries
 	UsedBy       []string
+	Baselined    bool `json:",omitempty"` // failure accepted by the baseline, so not treated as failed
+}
+
+// BinaryDecision is the outcome of evaluating a module against the policy of a binary that uses it
+type BinaryDecision struct {
+	Binary       string
+	Policy       string `json:",omitempty"` // name of the scoped policy applied, empty for the top-level config
+	Decision     Decision
+	NotPermitted []string `json:",omitempty"`
+	Severity     Severity `json:",omitempty"`
+}
+
+// Notice is an observation made during evaluation that does not alter the decision
+type Notice struct {
+	Severity Severity
+	Message  string
 }
 
 func (r EvaluatedModule) Allowed() bool {
-	return r.Decision == DecisionAllowed
+	return r.Decision.Allowed()
+}
+
+// IsUsedBy returns true if the module is used by the binary at the supplied path
+func (r EvaluatedModule) IsUsedBy(binPath string) bool {
+	for _, path := range r.UsedBy {
+		if path == binPath {
+			return true
+		}
+	}
+	return false
+}
+
+// Failed returns true if the decision or any notice has an error severity, and the failure has not been accepted by
+// the baseline
+func (r EvaluatedModule) Failed() bool {
+	return r.Severity == SeverityError && !r.Baselined
+}
+
+// Allowed returns true if the module is allowed for the binary
+func (b BinaryDecision) Allowed() bool {
+	return b.Decision.Allowed()
+}
+
+// combine folds the result of evaluating the module against another policy into this result. The overall decision
+// is the most severe of the decisions that did not allow the module.
+func (r *EvaluatedModule) combine(other EvaluatedModule) {
+	if !other.Allowed() && (r.Allowed() || other.Severity > r.Severity) {
+		r.Decision = other.Decision
+	}
+	for _, lic := range other.NotPermitted {
+		r.NotPermitted = appendUnique(r.NotPermitted, lic)
+	}
+	for _, rule := range other.Rules {
+		r.addRule(rule)
+	}
+	for _, n := range other.Notices {
+		if !r.hasNotice(n) {
+			r.Notices = append(r.Notices, n)
+		}
+	}
+	r.Severity = maxSeverity(r.Severity, other.Severity)
+}
+
+// hasNotice returns true if an identical notice has already been recorded
+func (r EvaluatedModule) hasNotice(notice Notice) bool {
+	for _, n := range r.Notices {
+		if n == notice {
+			return true
+		}
+	}
+	return false
+}
+
+// addNotice records a notice, raising the severity of the result if required
+func (r *EvaluatedModule) addNotice(severity Severity, format string, args ...interface{}) {
+	n := Notice{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if !r.hasNotice(n) {
+		r.Notices = append(r.Notices, n)
+	}
+	r.Severity = maxSeverity(r.Severity, severity)
+}
+
+// addRule records an applied override or exception, ignoring duplicates
+func (r *EvaluatedModule) addRule(rule Rule) {
+	if !r.hasRule(rule) {
+		r.Rules = append(r.Rules, rule)
+	}
+}
+
+// hasRule returns true if the override or exception was applied to the module
+func (r EvaluatedModule) hasRule(rule Rule) bool {
+	for _, existing := range r.Rules {
+		if existing.Type == rule.Type && existing.Policy == rule.Policy && existing.Path == rule.Path &&
+			existing.Version == rule.Version && equalStrings(existing.Licenses, rule.Licenses) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalStrings returns true if the lists hold the same values in the same order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func (r EvaluatedModule) ExplainDecision() string {
@@ -28,21 +145,94 @@ func (r EvaluatedModule) ExplainDecision() string {
 		return "allowed"
 	case DecisionNotAllowedUnresolvableLicense:
 		return "not allowed - unresolvable license"
+	case DecisionNotAllowedNoLicenseFiles:
+		return "not allowed - no license files found"
+	case DecisionNotAllowedUnclassifiedLicense:
+		if guess, found := r.bestGuess(); found {
+			return fmt.Sprintf("not allowed - license files could not be classified (best guess: %s, confidence %.2f)", guess.Name, guess.Confidence)
+		}
+		return "not allowed - license files could not be classified"
+	case DecisionNotAllowedModuleUnavailable:
+		if r.Unavailable != "" {
+			return fmt.Sprintf("not allowed - module source unavailable (%s)", r.Unavailable)
+		}
+		return "not allowed - module source unavailable"
 	case DecisionNotAllowedLicenseNotPermitted:
 		return fmt.Sprintf("not allowed - non-permitted licenses: %v", r.NotPermitted)
+	case DecisionAllowedModuleApproved:
+		return "allowed - module approved" + r.explainRule(RuleTypeModuleAllow)
+	case DecisionNotAllowedModuleDenied:
+		return "not allowed - module denied" + r.explainRule(RuleTypeModuleDeny)
 	default:
 		panic("unrecognised decision")
 	}
 }
 
+// ExplainFailure explains why the module failed, describing its decision along with any notices with an error
+// severity, which can fail a module that is allowed
+func (r EvaluatedModule) ExplainFailure() string {
+	explanation := r.ExplainDecision()
+	for _, n := range r.Notices {
+		if n.Severity == SeverityError {
+			explanation += "; " + n.Message
+		}
+	}
+	return explanation
+}
+
+// bestGuess returns the below threshold license match with the highest confidence, if any
+func (r EvaluatedModule) bestGuess() (best model.License, found bool) {
+	for _, lic := range r.BelowThreshold {
+		if !found || lic.Confidence > best.Confidence {
+			best, found = lic, true
+		}
+	}
+	return best, found
+}
+
+// explainRule describes the first applied rule of the given type, if any
+func (r EvaluatedModule) explainRule(ruleType RuleType) string {
+	for _, rule := range r.Rules {
+		if rule.Type != ruleType {
+			continue
+		}
+		if rule.Justification != "" {
+			return fmt.Sprintf(" by rule for %s (%s)", rule.Path, rule.Justification)
+		}
+		return fmt.Sprintf(" by rule for %s", rule.Path)
+	}
+	return ""
+}
+
 type Decision int
 
 const (
-	DecisionAllowed Decision = 1 + iota
-	DecisionNotAllowedUnresolvableLicense
+	DecisionAllowed                       Decision = 1 + iota
+	DecisionNotAllowedUnresolvableLicense          // reported by earlier versions, which did not distinguish the reasons below
 	DecisionNotAllowedLicenseNotPermitted
+	DecisionAllowedModuleApproved
+	DecisionNotAllowedModuleDenied
+	DecisionNotAllowedNoLicenseFiles
+	DecisionNotAllowedUnclassifiedLicense
+	DecisionNotAllowedModuleUnavailable
 )
 
+// Unresolvable returns true if the decision is the result of the module's licenses not being resolved
+func (d Decision) Unresolvable() bool {
+	switch d {
+	case DecisionNotAllowedUnresolvableLicense, DecisionNotAllowedNoLicenseFiles, DecisionNotAllowedUnclassifiedLicense,
+		DecisionNotAllowedModuleUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// Allowed returns true if the decision permits use of the module
+func (d Decision) Allowed() bool {
+	return d == DecisionAllowed || d == DecisionAllowedModuleApproved
+}
+
 func (d Decision) MarshalText() ([]byte, error) {
 	switch d {
 	case DecisionAllowed:
@@ -51,7 +241,41 @@ func (d Decision) MarshalText() ([]byte, error) {
 		return []byte("unresolvable-license"), nil
 	case DecisionNotAllowedLicenseNotPermitted:
 		return []byte("licenses-not-allowed"), nil
+	case DecisionAllowedModuleApproved:
+		return []byte("module-approved"), nil
+	case DecisionNotAllowedModuleDenied:
+		return []byte("module-denied"), nil
+	case DecisionNotAllowedNoLicenseFiles:
+		return []byte("no-license-files"), nil
+	case DecisionNotAllowedUnclassifiedLicense:
+		return []byte("unclassified-license"), nil
+	case DecisionNotAllowedModuleUnavailable:
+		return []byte("module-unavailable"), nil
 	default:
 		panic("unrecognised decision")
 	}
 }
+
+func (d *Decision) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "allowed":
+		*d = DecisionAllowed
+	case "unresolvable-license":
+		*d = DecisionNotAllowedUnresolvableLicense
+	case "licenses-not-allowed":
+		*d = DecisionNotAllowedLicenseNotPermitted
+	case "module-approved":
+		*d = DecisionAllowedModuleApproved
+	case "module-denied":
+		*d = DecisionNotAllowedModuleDenied
+	case "no-license-files":
+		*d = DecisionNotAllowedNoLicenseFiles
+	case "unclassified-license":
+		*d = DecisionNotAllowedUnclassifiedLicense
+	case "module-unavailable":
+		*d = DecisionNotAllowedModuleUnavailable
+	default:
+		return fmt.Errorf("unrecognised decision %q", string(b))
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/uw-labs/lichen/internal/match"
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+// RuleType identifies the type of config entry a rule originates from
+type RuleType string
+
+const (
+	RuleTypeOverride            RuleType = "override"
+	RuleTypeLicenseNotPermitted RuleType = "licenseNotPermitted"
+	RuleTypeUnresolvableLicense RuleType = "unresolvableLicense"
+	RuleTypeNoLicenseFiles      RuleType = "noLicenseFiles"
+	RuleTypeUnclassifiedLicense RuleType = "unclassifiedLicense"
+	RuleTypeModuleUnavailable   RuleType = "moduleUnavailable"
+	RuleTypeModuleAllow         RuleType = "moduleAllow"
+	RuleTypeModuleDeny          RuleType = "moduleDeny"
+)
+
+// Rule identifies a configured override or exception that was applied to a module
+type Rule struct {
+	Type     RuleType
+	Policy   string `json:",omitempty"` // name of the policy defining the rule, if not defined at the top level
+	Path     string
+	Version  string   `json:",omitempty"`
+	Licenses []string `json:",omitempty"`
+	Metadata
+}
+
+// entry is a compiled override or exception
+type entry struct {
+	Rule
+	path    match.Path
+	version match.Version
+}
+
+func newEntry(ruleType RuleType, path, version string, licenses []string, metadata Metadata, required []string) (entry, error) {
+	p, err := match.ParsePath(path)
+	if err != nil {
+		return entry{}, fmt.Errorf("invalid path: %w", err)
+	}
+	v, err := match.ParseVersion(version)
+	if err != nil {
+		return entry{}, fmt.Errorf("invalid version for %s: %w", path, err)
+	}
+	missing, err := metadata.missing(required)
+	if err != nil {
+		return entry{}, err
+	}
+	if len(missing) > 0 {
+		return entry{}, fmt.Errorf("%s for %s is missing required metadata: %s", ruleType, path, strings.Join(missing, ", "))
+	}
+	return entry{
+		Rule: Rule{
+			Type:     ruleType,
+			Path:     path,
+			Version:  version,
+			Licenses: licenses,
+			Metadata: metadata,
+		},
+		path:    p,
+		version: v,
+	}, nil
+}
+
+// matches returns true if the entry applies to the supplied module
+func (e entry) matches(mod model.Module) bool {
+	return e.path.Match(mod.Path) && e.version.Match(mod.Version)
+}
+
+// expired returns true if the rule has an expiry date that has passed. Rules apply up to and including the day
+// they expire.
+func (m Metadata) expired(now time.Time) bool {
+	return m.Expires != nil && now.After(m.Expires.Time().AddDate(0, 0, 1))
+}
+
+// expiresWithin returns true if the rule has not expired, but will do within the supplied number of days
+func (m Metadata) expiresWithin(now time.Time, days int) bool {
+	return m.Expires != nil && !m.expired(now) && now.AddDate(0, 0, days).After(m.Expires.Time())
+}
+
+// moreSpecific returns true if the entry is more specific than the other entry. Path patterns are compared first,
+// followed by versions: exact versions are more specific than constraints, which are more specific than no version.
+func (e entry) moreSpecific(other entry) bool {
+	if a, b := e.path.Specificity(), other.path.Specificity(); a != b {
+		return a > b
+	}
+	return versionSpecificity(e.version) > versionSpecificity(other.version)
+}
+
+func versionSpecificity(v match.Version) int {
+	switch {
+	case v.IsExact():
+		return 2
+	case v.IsAny():
+		return 0
+	default:
+		return 1
+	}
+}
+
+// coversLicense returns true if the entry applies to the named license, which is the case when no licenses are listed
+func (e entry) coversLicense(name string) bool {
+	if len(e.Licenses) == 0 {
+		return true
+	}
+	for _, lic := range e.Licenses {
+		if lic == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rules holds the compiled overrides and exceptions from the config
+type rules struct {
+	now                 time.Time
+	overrides           []entry
+	licenseNotPermitted []entry
+	unresolvableLicense []entry // apply to modules whose licenses are unresolvable for any reason
+	noLicenseFiles      []entry
+	unclassifiedLicense []entry
+	moduleUnavailable   []entry
+	modules             []entry // module allow and deny rules
+}
+
+// now is overridable for the purpose of testing expiry
+var now = time.Now
+
+// compileRules compiles the overrides and exceptions in the config, returning all problems encountered
+func compileRules(conf Config) (r rules, err error) {
+	r.now = now()
+	for i, o := range conf.Overrides {
+		e, eErr := newEntry(RuleTypeOverride, o.Path, o.Version, o.Licenses, o.Metadata, conf.RequireMetadata)
+		if eErr != nil {
+			err = multierror.Append(err, fmt.Errorf("override[%d]: %w", i, eErr))
+		}
+		r.overrides = append(r.overrides, e)
+	}
+	if exErr := r.addExceptions(conf.Exceptions, "", "exceptions", conf.RequireMetadata); exErr != nil {
+		err = multierror.Append(err, exErr)
+	}
+	for _, rs := range []struct {
+		ruleType RuleType
+		field    string
+		rules    []ModuleRule
+	}{
+		{ruleType: RuleTypeModuleDeny, field: "modules.deny", rules: conf.Modules.Deny},
+		{ruleType: RuleTypeModuleAllow, field: "modules.allow", rules: conf.Modules.Allow},
+	} {
+		for i, m := range rs.rules {
+			e, eErr := newEntry(rs.ruleType, m.Path, m.Version, nil, m.Metadata, conf.RequireMetadata)
+			if eErr != nil {
+				err = multierror.Append(err, fmt.Errorf("%s[%d]: %w", rs.field, i, eErr))
+			}
+			r.modules = append(r.modules, e)
+		}
+	}
+	if err != nil {
+		return rules{}, err
+	}
+	return r, nil
+}
+
+// addExceptions compiles and adds the supplied exceptions, recording the name of the policy they are defined by
+func (r *rules) addExceptions(exceptions Exceptions, policy, field string, required []string) (err error) {
+	for i, ex := range exceptions.LicenseNotPermitted {
+		e, eErr := newEntry(RuleTypeLicenseNotPermitted, ex.Path, ex.Version, ex.Licenses, ex.Metadata, required)
+		if eErr != nil {
+			err = multierror.Append(err, fmt.Errorf("%s.licenseNotPermitted[%d]: %w", field, i, eErr))
+		}
+		e.Policy = policy
+		r.licenseNotPermitted = append(r.licenseNotPermitted, e)
+	}
+	for _, es := range []struct {
+		ruleType   RuleType
+		exceptions []UnresolvableLicense
+		entries    *[]entry
+	}{
+		{ruleType: RuleTypeUnresolvableLicense, exceptions: exceptions.UnresolvableLicense, entries: &r.unresolvableLicense},
+		{ruleType: RuleTypeNoLicenseFiles, exceptions: exceptions.NoLicenseFiles, entries: &r.noLicenseFiles},
+		{ruleType: RuleTypeUnclassifiedLicense, exceptions: exceptions.UnclassifiedLicense, entries: &r.unclassifiedLicense},
+		{ruleType: RuleTypeModuleUnavailable, exceptions: exceptions.ModuleUnavailable, entries: &r.moduleUnavailable},
+	} {
+		for i, ex := range es.exceptions {
+			e, eErr := newEntry(es.ruleType, ex.Path, ex.Version, nil, ex.Metadata, required)
+			if eErr != nil {
+				err = multierror.Append(err, fmt.Errorf("%s.%s[%d]: %w", field, es.ruleType, i, eErr))
+			}
+			e.Policy = policy
+			*es.entries = append(*es.entries, e)
+		}
+	}
+	return err
+}
+
+// unresolvable returns the exceptions applicable to modules with the supplied unresolvable decision, with those
+// specific to the decision first
+func (r rules) unresolvable(d Decision) []entry {
+	var specific []entry
+	switch d {
+	case DecisionNotAllowedNoLicenseFiles:
+		specific = r.noLicenseFiles
+	case DecisionNotAllowedUnclassifiedLicense:
+		specific = r.unclassifiedLicense
+	case DecisionNotAllowedModuleUnavailable:
+		specific = r.moduleUnavailable
+	}
+	return append(append([]entry(nil), specific...), r.unresolvableLicense...)
+}
+
+// scoped returns a copy of the rules, with the exceptions of the supplied policy added
+func (r rules) scoped(p Policy, field string, required []string) (rules, error) {
+	scoped := rules{
+		now:                 r.now,
+		overrides:           r.overrides,
+		licenseNotPermitted: append([]entry(nil), r.licenseNotPermitted...),
+		unresolvableLicense: append([]entry(nil), r.unresolvableLicense...),
+		noLicenseFiles:      append([]entry(nil), r.noLicenseFiles...),
+		unclassifiedLicense: append([]entry(nil), r.unclassifiedLicense...),
+		moduleUnavailable:   append([]entry(nil), r.moduleUnavailable...),
+		modules:             r.modules,
+	}
+	if err := scoped.addExceptions(p.Exceptions, p.Name, field, required); err != nil {
+		return rules{}, err
+	}
+	return scoped, nil
+}
+
+// all returns every compiled entry
+func (r rules) all() []entry {
+	var all []entry
+	for _, entries := range [][]entry{r.overrides, r.licenseNotPermitted, r.unresolvableLicense, r.noLicenseFiles, r.unclassifiedLicense, r.moduleUnavailable, r.modules} {
+		all = append(all, entries...)
+	}
+	return all
+}
+
+// mostSpecific returns the most specific unexpired entry that applies to the module and satisfies the supplied
+// predicate. Where multiple entries are equally specific, the first configured wins.
+func (r rules) mostSpecific(entries []entry, mod model.Module, pred func(entry) bool) (entry, bool) {
+	var (
+		best  entry
+		found bool
+	)
+	for _, e := range entries {
+		if !e.matches(mod) || e.expired(r.now) || (pred != nil && !pred(e)) {
+			continue
+		}
+		if !found || e.moreSpecific(best) {
+			best, found = e, true
+		}
+	}
+	return best, found
+}
@@ -11,12 +11,32 @@ import (
 
 const defaultThreshold = 0.80
 
-func Run(ctx context.Context, conf Config, binPaths ...string) (Summary, error) {
+// Options control what is scanned, beyond the dependencies of each binary
+type Options struct {
+	MainModule bool     // include the main module of each binary
+	SourceDirs []string // source directories of main modules built from a source checkout
+	SBOMs      []string // SBOMs (CycloneDX or SPDX) to scan in place of binaries
+}
+
+func Run(ctx context.Context, conf Config, opts Options, binPaths ...string) (Summary, error) {
 	// extract modules details from each supplied binary
-	binaries, err := module.Extract(ctx, binPaths...)
+	binaries := make([]model.BuildInfo, 0, len(binPaths)+len(opts.SBOMs))
+	if len(binPaths) > 0 {
+		extracted, err := module.Extract(ctx, binPaths...)
+		if err != nil {
+			return Summary{}, err
+		}
+		binaries = append(binaries, extracted...)
+	}
+
+	// read the modules listed by each supplied SBOM, which are then evaluated in the same way as those of a binary
+	sboms, err := readSBOMs(opts.SBOMs)
 	if err != nil {
 		return Summary{}, err
 	}
+	for _, s := range sboms {
+		binaries = append(binaries, s.BuildInfo())
+	}
 
 	// fetch each module - this returns pertinent details, including the OS path to the module
 	modules, err := module.Fetch(ctx, uniqueModuleRefs(binaries))
@@ -24,33 +44,72 @@ func Run(ctx context.Context, conf Config, binPaths ...string) (Summary, error)
 		return Summary{}, err
 	}
 
-	// resolve licenses based on a minimum threshold
-	threshold := defaultThreshold
-	if conf.Threshold != nil {
-		threshold = *conf.Threshold
+	// locate the main module of each binary, if requested
+	if opts.MainModule {
+		mainMods, err := mainModules(ctx, binaries, opts.SourceDirs)
+		if err != nil {
+			return Summary{}, err
+		}
+		modules = mergeMainModules(modules, mainMods)
 	}
-	modules, err = license.Resolve(modules, threshold)
+
+	// resolve licenses based on minimum thresholds
+	modules, err = license.Resolve(modules, conf.thresholds())
+	if err != nil {
+		return Summary{}, err
+	}
+
+	// compare the licenses declared by the SBOMs with those detected, prior to any overrides
+	comparisons := compareLicenses(sboms, modules)
+
+	summary, err := Evaluate(conf, binaries, modules)
+	if err != nil {
+		return Summary{}, err
+	}
+	summary.Comparisons = comparisons
+	return summary, nil
+}
+
+// Evaluate evaluates the modules used by the supplied binaries against the config, once their licenses have been
+// resolved
+func Evaluate(conf Config, binaries []model.BuildInfo, modules []model.Module) (Summary, error) {
+	// compile the top-level config, along with any policies scoped to particular binaries
+	policies, err := compilePolicies(conf)
 	if err != nil {
 		return Summary{}, err
 	}
 
 	// apply any overrides, if configured
-	if len(conf.Overrides) > 0 {
-		modules = applyOverrides(modules, conf.Overrides)
+	var overridden map[model.ModuleReference]override
+	if len(policies[0].rules.overrides) > 0 {
+		modules, overridden = applyOverrides(modules, policies[0].rules)
 	}
 
 	// evaluate the modules and sort by path
-	results := evaluate(conf, binaries, modules)
+	results := evaluate(conf, policies, binaries, modules, overridden)
 	sort.Slice(results, func(i, j int) bool {
 		return results[i].Module.Path < results[j].Module.Path
 	})
 
 	return Summary{
-		Binaries: binaries,
-		Modules:  results,
+		Binaries:   binaries,
+		Modules:    results,
+		StaleRules: staleRules(policies, results, overridden, conf.Severity.StaleRules.or(SeverityWarn)),
 	}, nil
 }
 
+// thresholds returns the minimum confidence required for a match of each license to count
+func (c Config) thresholds() license.Thresholds {
+	t := license.Thresholds{
+		Default:  defaultThreshold,
+		Licenses: c.Thresholds,
+	}
+	if c.Threshold != nil {
+		t.Default = *c.Threshold
+	}
+	return t
+}
+
 // uniqueModuleRefs returns all unique modules referenced by the supplied binaries
 func uniqueModuleRefs(infos []model.BuildInfo) []model.ModuleReference {
 	unique := make(map[model.ModuleReference]struct{})
@@ -68,100 +127,50 @@ func uniqueModuleRefs(infos []model.BuildInfo) []model.ModuleReference {
 	return refs
 }
 
-// applyOverrides replaces license information
-func applyOverrides(modules []model.Module, overrides []Override) []model.Module {
-	type replacement struct {
-		version  string
-		licenses []string
-	}
-	replacements := make(map[string]replacement, len(overrides))
-	for _, o := range overrides {
-		replacements[o.Path] = replacement{
-			version:  o.Version,
-			licenses: o.Licenses,
-		}
-	}
-
-	for i, mod := range modules {
-		if repl, found := replacements[mod.ModuleReference.Path]; found {
-			// if an explicit version is configured, only apply the override if the module version matches
-			if repl.version != "" && repl.version != mod.Version {
-				continue
-			}
-			mod.Licenses = make([]model.License, 0, len(repl.licenses))
-			for _, lic := range repl.licenses {
-				mod.Licenses = append(mod.Licenses, model.License{
-					Name:       lic,
-					Confidence: 1,
-				})
+// mergeMainModules adds the main modules, marking any that are also dependencies of other binaries as such
+func mergeMainModules(modules, mainMods []model.Module) []model.Module {
+	for _, main := range mainMods {
+		var found bool
+		for i, m := range modules {
+			if m.ModuleReference == main.ModuleReference {
+				modules[i].Main, found = true, true
 			}
-			modules[i] = mod
+		}
+		if !found {
+			modules = append(modules, main)
 		}
 	}
-
 	return modules
 }
 
-// evaluate inspects each module, checking that (a) license details could be determined, and (b) licenses
-// are permitted by the supplied configuration.
-func evaluate(conf Config, binaries []model.BuildInfo, modules []model.Module) []EvaluatedModule {
-	// build a map each module to binaries that reference them
-	binRefs := make(map[model.ModuleReference][]string, len(modules))
-	for _, bin := range binaries {
-		for _, ref := range bin.ModuleRefs {
-			binRefs[ref] = append(binRefs[ref], bin.Path)
-		}
-	}
-
-	// build a map of permitted licenses
-	permitted := make(map[string]bool, len(conf.Allow))
-	for _, lic := range conf.Allow {
-		permitted[lic] = true
-	}
+// override records an override applied to a module, along with the licenses it replaced
+type override struct {
+	Rule
+	replaced []model.License
+}
 
-	// check each module
-	results := make([]EvaluatedModule, 0, len(modules))
-	for _, mod := range modules {
-		res := EvaluatedModule{
-			Module:   mod,
-			UsedBy:   binRefs[mod.ModuleReference],
-			Decision: DecisionAllowed,
+// applyOverrides replaces license information, returning the updated modules along with the override applied to each
+func applyOverrides(modules []model.Module, rules rules) ([]model.Module, map[model.ModuleReference]override) {
+	applied := make(map[model.ModuleReference]override)
+	modules = append([]model.Module(nil), modules...)
+	for i, mod := range modules {
+		o, found := rules.mostSpecific(rules.overrides, mod, nil)
+		if !found {
+			continue
 		}
-		if len(mod.Licenses) == 0 && !ignoreUnresolvable(conf, mod) {
-			res.Decision = DecisionNotAllowedUnresolvableLicense
+		applied[mod.ModuleReference] = override{
+			Rule:     o.Rule,
+			replaced: mod.Licenses,
 		}
-		for _, lic := range mod.Licenses {
-			if len(permitted) > 0 && !permitted[lic.Name] && !ignoreNotPermitted(conf, mod, lic) {
-				res.Decision = DecisionNotAllowedLicenseNotPermitted
-				res.NotPermitted = append(res.NotPermitted, lic.Name)
-			}
+		mod.Licenses, mod.BelowThreshold = make([]model.License, 0, len(o.Licenses)), nil
+		for _, lic := range o.Licenses {
+			mod.Licenses = append(mod.Licenses, model.License{
+				Name:       lic,
+				Confidence: 1,
+			})
 		}
-		results = append(results, res)
+		modules[i] = mod
 	}
-	return results
-}
 
-func ignoreUnresolvable(conf Config, mod model.Module) bool {
-	for _, exception := range conf.Exceptions.UnresolvableLicense {
-		if exception.Path == mod.Path && (exception.Version == "" || exception.Version == mod.Version) {
-			return true
-		}
-	}
-	return false
-}
-
-func ignoreNotPermitted(conf Config, mod model.Module, lic model.License) bool {
-	for _, exception := range conf.Exceptions.LicenseNotPermitted {
-		if exception.Path == mod.Path && (exception.Version == "" || exception.Version == mod.Version) {
-			if len(exception.Licenses) == 0 {
-				return true
-			}
-			for _, exceptionLicense := range exception.Licenses {
-				if exceptionLicense == lic.Name {
-					return true
-				}
-			}
-		}
-	}
-	return false
+	return modules, applied
 }
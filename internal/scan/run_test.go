@@ -0,0 +1,149 @@
+package scan_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// writeSBOM writes a CycloneDX SBOM for the main module with the supplied purl, listing the supplied components
+func writeSBOM(t *testing.T, mainPURL string, componentPURLs ...string) string {
+	components := make([]string, 0, len(componentPURLs))
+	for _, purl := range componentPURLs {
+		components = append(components, fmt.Sprintf(`{"type": "library", "purl": %q}`, purl))
+	}
+	path := filepath.Join(t.TempDir(), "sbom.cdx.json")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "metadata": {"component": {"type": "application", "purl": %q}},
+  "components": [%s]
+}`, mainPURL, strings.Join(components, ", "))), 0644))
+	return path
+}
+
+func TestRun_ModuleUnavailable(t *testing.T) {
+	// modules that can't be downloaded are evaluated, rather than failing the scan
+	t.Setenv("GOPROXY", "off")
+	path := writeSBOM(t, "pkg:golang/github.com/acme/app@v1.0.0", "pkg:golang/github.com/uw-labs/lichen-unavailable@v1.0.0")
+
+	summary, err := scan.Run(context.Background(), scan.Config{}, scan.Options{SBOMs: []string{path}})
+	require.NoError(t, err)
+	require.Len(t, summary.Modules, 1)
+	m := summary.Modules[0]
+	assert.Equal(t, "github.com/uw-labs/lichen-unavailable", m.Path)
+	assert.Empty(t, m.Dir)
+	assert.Equal(t, scan.DecisionNotAllowedModuleUnavailable, m.Decision)
+	assert.Equal(t, "not allowed - module source unavailable (github.com/uw-labs/lichen-unavailable@v1.0.0: module lookup disabled by GOPROXY=off)", m.ExplainDecision())
+}
+
+func TestRun_MainModule(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	license, err := os.ReadFile("../../LICENSE")
+	require.NoError(t, err)
+
+	// source creates a source directory for the module with the supplied path, licensed under MIT
+	source := func(t *testing.T, path string, license []byte) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+path+"\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "LICENSE"), license, 0644))
+		return dir
+	}
+	// only part of the license is included by a partial license file, so it is matched below the threshold
+	words := strings.Fields(string(license))
+	partial := []byte(strings.Join(words[:len(words)*7/10], " "))
+
+	testCases := []struct {
+		name        string
+		purl        string
+		source      string // path of the module declared by the source directory, if any
+		partial     bool   // if the source directory has a partial license file
+		decision    scan.Decision
+		licenses    int
+		explanation string
+		err         string
+	}{
+		{
+			name:        "source checkout",
+			purl:        "pkg:golang/github.com/acme/app@(devel)",
+			source:      "github.com/acme/app",
+			decision:    scan.DecisionAllowed,
+			licenses:    1,
+			explanation: "allowed",
+		},
+		{
+			name:        "source checkout with partial license",
+			purl:        "pkg:golang/github.com/acme/app@(devel)",
+			source:      "github.com/acme/app",
+			partial:     true,
+			decision:    scan.DecisionNotAllowedUnclassifiedLicense,
+			explanation: "not allowed - license files could not be classified (best guess: MIT, confidence 0.70)",
+		},
+		{
+			name:        "source checkout without source directory",
+			purl:        "pkg:golang/github.com/acme/app@(devel)",
+			decision:    scan.DecisionNotAllowedModuleUnavailable,
+			explanation: "not allowed - module source unavailable (built from a source checkout, and no source directory was supplied via --source)",
+		},
+		{
+			name:     "dirty checkout",
+			purl:     "pkg:golang/github.com/acme/app@v1.0.1-0.20240101000000-abcdef123456+dirty",
+			source:   "github.com/acme/other",
+			decision: scan.DecisionNotAllowedModuleUnavailable,
+		},
+		{
+			name:     "versioned, but unavailable",
+			purl:     "pkg:golang/github.com/acme/app@v1.0.0",
+			decision: scan.DecisionNotAllowedModuleUnavailable,
+		},
+		{
+			name:   "source directory without go.mod",
+			purl:   "pkg:golang/github.com/acme/app@(devel)",
+			source: "-",
+			err:    "failed to read go.mod of source directory",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			opts := scan.Options{MainModule: true, SBOMs: []string{writeSBOM(t, tc.purl)}}
+			switch tc.source {
+			case "":
+			case "-":
+				opts.SourceDirs = []string{t.TempDir()}
+			default:
+				content := license
+				if tc.partial {
+					content = partial
+				}
+				opts.SourceDirs = []string{source(t, tc.source, content)}
+			}
+
+			summary, err := scan.Run(context.Background(), scan.Config{}, opts)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, summary.Modules, 1)
+			m := summary.Modules[0]
+			assert.Equal(t, "github.com/acme/app", m.Path)
+			assert.True(t, m.Main)
+			assert.Equal(t, tc.decision, m.Decision)
+			if tc.explanation != "" {
+				assert.Equal(t, tc.explanation, m.ExplainDecision())
+			}
+			assert.Len(t, m.Licenses, tc.licenses)
+			assert.Equal(t, []string{summary.Binaries[0].Path}, m.UsedBy)
+		})
+	}
+}
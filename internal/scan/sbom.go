@@ -0,0 +1,99 @@
+package scan
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/sbom"
+)
+
+// LicenseComparison compares the licenses declared for a module by an SBOM with those lichen detected in the module's
+// license files
+type LicenseComparison struct {
+	SBOM     string                // OS level absolute path to the SBOM
+	Module   model.ModuleReference // the module the licenses were declared for
+	Declared []string              // licenses declared by the SBOM
+	Detected []string              // licenses detected in the module's license files, prior to any overrides
+}
+
+// Matches returns true if the declared and detected licenses are the same
+func (c LicenseComparison) Matches() bool {
+	if len(c.Declared) != len(c.Detected) {
+		return false
+	}
+	for i := range c.Declared {
+		if c.Declared[i] != c.Detected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Explain returns a description of the declared and detected licenses
+func (c LicenseComparison) Explain() string {
+	detected := "none"
+	if len(c.Detected) > 0 {
+		detected = strings.Join(c.Detected, ", ")
+	}
+	return "declared " + strings.Join(c.Declared, ", ") + ", detected " + detected
+}
+
+// readSBOMs reads the supplied SBOMs
+func readSBOMs(paths []string) ([]sbom.SBOM, error) {
+	sboms := make([]sbom.SBOM, 0, len(paths))
+	for _, path := range paths {
+		s, err := sbom.Read(path)
+		if err != nil {
+			return nil, err
+		}
+		sboms = append(sboms, s)
+	}
+	return sboms, nil
+}
+
+// compareLicenses compares the licenses declared by the SBOMs with those resolved for each module, for every module
+// the SBOMs declare licenses for
+func compareLicenses(sboms []sbom.SBOM, modules []model.Module) []LicenseComparison {
+	resolved := make(map[model.ModuleReference]model.Module, len(modules))
+	for _, m := range modules {
+		resolved[m.ModuleReference] = m
+	}
+
+	var comparisons []LicenseComparison
+	for _, s := range sboms {
+		for _, c := range s.Components {
+			m, found := resolved[c.ModuleReference]
+			if !found || c.Declared == nil {
+				continue
+			}
+			comparisons = append(comparisons, LicenseComparison{
+				SBOM:     s.Path,
+				Module:   c.ModuleReference,
+				Declared: c.Declared,
+				Detected: licenseNames(m.Licenses),
+			})
+		}
+	}
+	sort.SliceStable(comparisons, func(i, j int) bool {
+		if comparisons[i].SBOM != comparisons[j].SBOM {
+			return comparisons[i].SBOM < comparisons[j].SBOM
+		}
+		return comparisons[i].Module.Path < comparisons[j].Module.Path
+	})
+	return comparisons
+}
+
+// licenseNames returns the unique, sorted names of the licenses
+func licenseNames(licenses []model.License) []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, lic := range licenses {
+		if !seen[lic.Name] {
+			seen[lic.Name] = true
+			names = append(names, lic.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,64 @@
+package scan
+
+import (
+	"fmt"
+)
+
+// Severity indicates how seriously a policy violation should be treated
+type Severity int
+
+const (
+	SeverityInfo Severity = 1 + iota
+	SeverityWarn
+	SeverityError
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	default:
+		return ""
+	}
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "info":
+		*s = SeverityInfo
+	case "warn", "warning":
+		*s = SeverityWarn
+	case "error":
+		*s = SeverityError
+	default:
+		return fmt.Errorf("unrecognised severity %q (expected one of: error, warn, info)", string(b))
+	}
+	return nil
+}
+
+// or returns the severity if set, otherwise the supplied fallback
+func (s Severity) or(fallback Severity) Severity {
+	if s == 0 {
+		return fallback
+	}
+	return s
+}
+
+// maxSeverity returns the highest of the supplied severities
+func maxSeverity(severities ...Severity) (max Severity) {
+	for _, s := range severities {
+		if s > max {
+			max = s
+		}
+	}
+	return max
+}
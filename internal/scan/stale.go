@@ -0,0 +1,108 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/model"
+)
+
+// StaleRule is a configured override or exception that served no purpose in the scan
+type StaleRule struct {
+	Rule
+	Reason   string
+	Severity Severity
+}
+
+// staleRules returns each override or exception that either matched no scanned module, was not required by any
+// module it matched, or (in the case of overrides) only replaced licenses with those already detected
+func staleRules(policies []policy, results []EvaluatedModule, overridden map[model.ModuleReference]override, severity Severity) []StaleRule {
+	// the rules of scoped policies include those of the top-level config, which are only checked once
+	r := policies[0].rules
+	entries := r.all()
+	for _, p := range policies[1:] {
+		for _, e := range p.rules.all() {
+			if e.Policy == p.name {
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	var stale []StaleRule
+	for _, e := range entries {
+		if reason := staleReason(r, e, results, overridden); reason != "" {
+			stale = append(stale, StaleRule{
+				Rule:     e.Rule,
+				Reason:   reason,
+				Severity: severity,
+			})
+		}
+	}
+	return stale
+}
+
+// staleReason returns the reason the entry is stale, or an empty string if it is not
+func staleReason(r rules, e entry, results []EvaluatedModule, overridden map[model.ModuleReference]override) string {
+	var (
+		versions []string
+		matched  []EvaluatedModule
+	)
+	for _, res := range results {
+		if !e.path.Match(res.Path) {
+			continue
+		}
+		if !e.version.Match(res.Version) {
+			versions = append(versions, res.ModuleReference.String())
+			continue
+		}
+		matched = append(matched, res)
+	}
+
+	switch {
+	case len(matched) == 0 && len(versions) == 0:
+		return "matched no scanned module"
+	case len(matched) == 0:
+		sort.Strings(versions)
+		return fmt.Sprintf("version %q matched none of the scanned versions (%s)", e.Version, strings.Join(versions, ", "))
+	case e.expired(r.now):
+		// expired entries are reported against each module they match
+		return ""
+	}
+
+	var applied, redundant = false, true
+	for _, res := range matched {
+		if !res.hasRule(e.Rule) {
+			continue
+		}
+		applied = true
+		if o := overridden[res.ModuleReference]; e.Type != RuleTypeOverride || !sameLicenses(o.replaced, o.Licenses) {
+			redundant = false
+		}
+	}
+	switch {
+	case !applied:
+		return "not required by any scanned module"
+	case redundant:
+		return fmt.Sprintf("licenses are already detected as %s", strings.Join(e.Licenses, ", "))
+	default:
+		return ""
+	}
+}
+
+// sameLicenses returns true if the detected licenses have exactly the supplied names
+func sameLicenses(detected []model.License, names []string) bool {
+	unique := make(map[string]bool, len(detected))
+	for _, lic := range detected {
+		unique[lic.Name] = true
+	}
+	if len(unique) != len(names) {
+		return false
+	}
+	for _, name := range names {
+		if !unique[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/uw-labs/lichen/internal/license"
+)
+
+// licenseRefPrefix prefixes custom SPDX license identifiers, which are accepted without validation
+const licenseRefPrefix = "LicenseRef-"
+
+// Validate checks the config for mistakes that would otherwise go unnoticed: unknown license and category names,
+// invalid path patterns and version constraints, missing metadata and out of range values. All problems found are
+// returned. Licenses are known if they can be produced by classification, are listed under a configured category,
+// or are prefixed with "LicenseRef-". Maps are checked in order of their keys, so that problems are returned in a
+// consistent order.
+func (c Config) Validate() error {
+	names, err := license.Names()
+	if err != nil {
+		return err
+	}
+	v := validator{
+		licenses:   make(map[string]bool, len(names)),
+		categories: make(map[string]bool),
+	}
+	for _, name := range names {
+		v.licenses[name] = true
+	}
+	for _, category := range license.Categories {
+		v.categories[string(category)] = true
+	}
+	categories := make([]string, 0, len(c.Categories))
+	for category := range c.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		if v.licenses[category] {
+			v.fail("categories.%s: category has the same name as a license", category)
+		}
+		v.categories[category] = true
+		for _, lic := range c.Categories[category] {
+			v.licenses[lic] = true
+		}
+	}
+
+	if c.Threshold != nil {
+		v.checkConfidence("threshold", *c.Threshold)
+	}
+	thresholds := make([]string, 0, len(c.Thresholds))
+	for lic := range c.Thresholds {
+		thresholds = append(thresholds, lic)
+	}
+	sort.Strings(thresholds)
+	for _, lic := range thresholds {
+		v.checkLicense("thresholds", lic)
+		v.checkConfidence(fmt.Sprintf("thresholds.%s", lic), c.Thresholds[lic])
+	}
+	if lc := c.Severity.LowConfidence; lc != nil {
+		v.checkConfidence("severity.lowConfidence.threshold", lc.Threshold)
+	}
+	if c.ExpiryWarningDays < 0 {
+		v.fail("expiryWarningDays: must not be negative, received %d", c.ExpiryWarningDays)
+	}
+	for i, name := range c.Allow {
+		v.checkLicenseOrCategory(fmt.Sprintf("allow[%d]", i), name)
+	}
+	for i, name := range c.Deny {
+		v.checkLicenseOrCategory(fmt.Sprintf("deny[%d]", i), name)
+	}
+	severities := make([]string, 0, len(c.Severity.Licenses))
+	for name := range c.Severity.Licenses {
+		severities = append(severities, name)
+	}
+	sort.Strings(severities)
+	for _, name := range severities {
+		v.checkLicenseOrCategory("severity.licenses", name)
+	}
+	for i, o := range c.Overrides {
+		if len(o.Licenses) == 0 {
+			v.fail("override[%d]: at least one license must be listed for %s", i, o.Path)
+		}
+		for _, lic := range o.Licenses {
+			v.checkLicense(fmt.Sprintf("override[%d]", i), lic)
+		}
+	}
+	for i, ex := range c.Exceptions.LicenseNotPermitted {
+		for _, lic := range ex.Licenses {
+			v.checkLicense(fmt.Sprintf("exceptions.licenseNotPermitted[%d]", i), lic)
+		}
+	}
+	for i, p := range c.Policies {
+		for j, name := range p.Allow {
+			v.checkLicenseOrCategory(fmt.Sprintf("policies[%d].allow[%d]", i, j), name)
+		}
+		for j, name := range p.Deny {
+			v.checkLicenseOrCategory(fmt.Sprintf("policies[%d].deny[%d]", i, j), name)
+		}
+		for j, ex := range p.Exceptions.LicenseNotPermitted {
+			for _, lic := range ex.Licenses {
+				v.checkLicense(fmt.Sprintf("policies[%d].exceptions.licenseNotPermitted[%d]", i, j), lic)
+			}
+		}
+	}
+	if _, err := compilePolicies(c); err != nil {
+		v.err = multierror.Append(v.err, err)
+	}
+	return v.err
+}
+
+type validator struct {
+	licenses   map[string]bool
+	categories map[string]bool
+	err        error
+}
+
+func (v *validator) fail(format string, args ...interface{}) {
+	v.err = multierror.Append(v.err, fmt.Errorf(format, args...))
+}
+
+func (v *validator) checkConfidence(field string, value float64) {
+	if value <= 0 || value > 1 {
+		v.fail("%s: must be greater than 0 and at most 1, received %v", field, value)
+	}
+}
+
+func (v *validator) checkLicense(field, name string) {
+	if v.licenses[name] || strings.HasPrefix(name, licenseRefPrefix) {
+		return
+	}
+	v.fail("%s: unknown license %q%s", field, name, suggest(name, v.licenses))
+}
+
+func (v *validator) checkLicenseOrCategory(field, name string) {
+	if v.licenses[name] || v.categories[name] || strings.HasPrefix(name, licenseRefPrefix) {
+		return
+	}
+	candidates := make(map[string]bool, len(v.licenses)+len(v.categories))
+	for n := range v.licenses {
+		candidates[n] = true
+	}
+	for n := range v.categories {
+		candidates[n] = true
+	}
+	v.fail("%s: unknown license or category %q%s", field, name, suggest(name, candidates))
+}
+
+// suggest returns a hint naming the candidate closest to the supplied name, if there is a reasonably close one
+func suggest(name string, candidates map[string]bool) string {
+	sorted := make([]string, 0, len(candidates))
+	for candidate := range candidates {
+		sorted = append(sorted, candidate)
+	}
+	sort.Strings(sorted)
+
+	var (
+		best     string
+		bestDist = len(name)/3 + 2
+	)
+	for _, candidate := range sorted {
+		if strings.EqualFold(candidate, name) {
+			return fmt.Sprintf(" (did you mean %q?)", candidate)
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(candidate)); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of adjacent characters
+// required to turn one string into the other
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
@@ -0,0 +1,357 @@
+package spdx
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/license"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+	"golang.org/x/mod/module"
+)
+
+const (
+	version         = "SPDX-2.3"
+	dataLicense     = "CC0-1.0"
+	documentID      = "SPDXRef-DOCUMENT"
+	noAssertion     = "NOASSERTION"
+	namespacePrefix = "https://spdx.org/spdxdocs/lichen-"
+	creator         = "Tool: lichen"
+
+	// licenseRefPrefix prefixes custom license identifiers, used for licenses that aren't on the SPDX license list
+	licenseRefPrefix = "LicenseRef-"
+)
+
+// Document is an SPDX 2.3 document, with the subset of fields populated by lichen
+type Document struct {
+	SPDXVersion       string         `json:"spdxVersion"`
+	DataLicense       string         `json:"dataLicense"`
+	SPDXID            string         `json:"SPDXID"`
+	Name              string         `json:"name"`
+	DocumentNamespace string         `json:"documentNamespace"`
+	CreationInfo      CreationInfo   `json:"creationInfo"`
+	Packages          []Package      `json:"packages"`
+	Relationships     []Relationship `json:"relationships"`
+
+	// HasExtractedLicensingInfos describes the licenses referenced by "LicenseRef-" identifiers
+	HasExtractedLicensingInfos []ExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type CreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type Package struct {
+	SPDXID           string        `json:"SPDXID"`
+	Name             string        `json:"name"`
+	VersionInfo      string        `json:"versionInfo,omitempty"`
+	DownloadLocation string        `json:"downloadLocation"`
+	FilesAnalyzed    bool          `json:"filesAnalyzed"`
+	Checksums        []Checksum    `json:"checksums,omitempty"`
+	LicenseConcluded string        `json:"licenseConcluded"`
+	LicenseDeclared  string        `json:"licenseDeclared"`
+	CopyrightText    string        `json:"copyrightText"`
+	Comment          string        `json:"comment,omitempty"`
+	ExternalRefs     []ExternalRef `json:"externalRefs,omitempty"`
+}
+
+type Checksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type ExtractedLicensingInfo struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name"`
+}
+
+type Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// Combined returns a single document describing every scanned binary, along with the modules they depend on
+func Combined(summary scan.Summary, created time.Time) (Document, error) {
+	return newDocument("lichen", summary.Binaries, summary.Modules, created)
+}
+
+// PerBinary returns a document for each scanned binary, describing the binary along with the modules it depends on
+func PerBinary(summary scan.Summary, created time.Time) ([]Document, error) {
+	docs := make([]Document, 0, len(summary.Binaries))
+	for _, bin := range summary.Binaries {
+		var modules []scan.EvaluatedModule
+		for _, m := range summary.Modules {
+			if m.IsUsedBy(bin.Path) {
+				modules = append(modules, m)
+			}
+		}
+		doc, err := newDocument(filepath.Base(bin.Path), []model.BuildInfo{bin}, modules, created)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func newDocument(name string, binaries []model.BuildInfo, modules []scan.EvaluatedModule, created time.Time) (Document, error) {
+	namespace, err := uniqueNamespace(name)
+	if err != nil {
+		return Document{}, err
+	}
+	doc := Document{
+		SPDXVersion:       version,
+		DataLicense:       dataLicense,
+		SPDXID:            documentID,
+		Name:              name,
+		DocumentNamespace: namespace,
+		CreationInfo: CreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{creator},
+		},
+	}
+
+	ids := make(idAllocator)
+	refs := extractedLicenses{indices: make(map[string]int)}
+	binIDs := make(map[string]string, len(binaries))
+	mains := make(map[string]model.ModuleReference, len(binaries))
+	for _, bin := range binaries {
+		id := ids.allocate("Binary-" + filepath.Base(bin.Path))
+		binIDs[bin.Path], mains[bin.Path] = id, bin.MainModule()
+		doc.Packages = append(doc.Packages, binaryPackage(id, bin))
+		doc.Relationships = append(doc.Relationships, Relationship{
+			SPDXElementID:      documentID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+	for _, m := range modules {
+		id := ids.allocate("Module-" + m.ModuleReference.String())
+		doc.Packages = append(doc.Packages, modulePackage(id, m, &refs))
+		for _, bin := range m.UsedBy {
+			binID, found := binIDs[bin]
+			if !found {
+				continue
+			}
+			// binaries are built from their main module, rather than depending on it
+			relationship := "DEPENDS_ON"
+			if mains[bin] == m.ModuleReference {
+				relationship = "CONTAINS"
+			}
+			doc.Relationships = append(doc.Relationships, Relationship{
+				SPDXElementID:      binID,
+				RelationshipType:   relationship,
+				RelatedSPDXElement: id,
+			})
+		}
+	}
+	doc.HasExtractedLicensingInfos = refs.infos()
+	return doc, nil
+}
+
+func binaryPackage(id string, bin model.BuildInfo) Package {
+	p := Package{
+		SPDXID:           id,
+		Name:             filepath.Base(bin.Path),
+		VersionInfo:      bin.ModuleVersion,
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+	}
+	if bin.PackagePath != "" {
+		p.Comment = fmt.Sprintf("built from package %s", bin.PackagePath)
+	}
+	return p
+}
+
+func modulePackage(id string, m scan.EvaluatedModule, refs *extractedLicenses) Package {
+	decision, _ := m.Decision.MarshalText()
+	// the licenses detected in the module's license files, including those replaced by an override
+	detected := append(append([]model.License(nil), m.Licenses...), m.Overridden...)
+	p := Package{
+		SPDXID:           id,
+		Name:             m.Path,
+		VersionInfo:      m.Version,
+		DownloadLocation: downloadLocation(m.ModuleReference),
+		LicenseConcluded: expression(m.Licenses, false, refs),
+		LicenseDeclared:  expression(detected, true, refs),
+		CopyrightText:    copyrightText(detected),
+		Comment:          fmt.Sprintf("lichen decision: %s", decision),
+	}
+	if m.ZipSHA256 != "" {
+		p.Checksums = []Checksum{{Algorithm: "SHA256", ChecksumValue: m.ZipSHA256}}
+	}
+	if purl := m.PackageURL(); purl != "" {
+		p.ExternalRefs = []ExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  purl,
+		}}
+	}
+	return p
+}
+
+// downloadLocation returns the module proxy URL of the module's zip, if it has a released version
+func downloadLocation(ref model.ModuleReference) string {
+	if ref.PackageURL() == "" || strings.HasSuffix(ref.Version, "+dirty") {
+		return noAssertion
+	}
+	path, err := module.EscapePath(ref.Path)
+	if err != nil {
+		return noAssertion
+	}
+	version, err := module.EscapeVersion(ref.Version)
+	if err != nil {
+		return noAssertion
+	}
+	return fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.zip", path, version)
+}
+
+// expression returns an SPDX license expression requiring each of the licenses. Declared licenses only include those
+// found in license files, excluding those set by overrides (which supply the licenses they replaced instead). Licenses that aren't on the SPDX license list are referenced
+// by "LicenseRef-" identifiers.
+func expression(licenses []model.License, declared bool, refs *extractedLicenses) string {
+	var names []string
+	for _, lic := range licenses {
+		if declared && lic.Path == "" {
+			continue
+		}
+		if id := refs.identifier(lic); !contains(names, id) {
+			names = append(names, id)
+		}
+	}
+	if len(names) == 0 {
+		return noAssertion
+	}
+	sort.Strings(names)
+	if len(names) == 1 {
+		return names[0]
+	}
+	return "(" + strings.Join(names, " AND ") + ")"
+}
+
+// copyrightRgx matches lines starting with a copyright statement, e.g. "Copyright (c) 2009 The Go Authors", ignoring
+// mentions of copyright in the license terms
+var copyrightRgx = regexp.MustCompile(`(?im)^[\s#*/]*((?:copyright\s+(?:\(c\)|©|\d{4})|©\s*\d{4}).*?)\s*$`)
+
+// copyrightText returns the copyright statements found in the license files, or NOASSERTION if there are none
+func copyrightText(licenses []model.License) string {
+	var (
+		statements []string
+		seen       = make(map[string]bool)
+	)
+	for _, lic := range licenses {
+		if seen[lic.Path] {
+			continue
+		}
+		seen[lic.Path] = true
+		for _, match := range copyrightRgx.FindAllStringSubmatch(lic.Content, -1) {
+			if statement := strings.TrimSpace(match[1]); !contains(statements, statement) {
+				statements = append(statements, statement)
+			}
+		}
+	}
+	if len(statements) == 0 {
+		return noAssertion
+	}
+	return strings.Join(statements, "\n")
+}
+
+// uniqueNamespace returns a document namespace, which SPDX requires to be unique for each document
+func uniqueNamespace(name string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate document namespace: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%s%s-%x-%x-%x-%x-%x", namespacePrefix, idRgx.ReplaceAllString(name, "-"), b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+var idRgx = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// extractedLicenses allocates "LicenseRef-" identifiers to licenses that aren't on the SPDX license list, recording the
+// license text that SPDX requires to accompany them
+type extractedLicenses struct {
+	extracted []ExtractedLicensingInfo
+	indices   map[string]int // indices of extracted licenses, keyed by license name
+}
+
+// identifier returns the identifier by which the license is referenced in license expressions
+func (e *extractedLicenses) identifier(lic model.License) string {
+	if license.IsSPDX(lic.Name) {
+		return lic.Name
+	}
+	i, found := e.indices[lic.Name]
+	if !found {
+		base := licenseRefPrefix + strings.Trim(idRgx.ReplaceAllString(strings.TrimPrefix(lic.Name, licenseRefPrefix), "-"), "-")
+		id := base
+		for n := 2; e.allocated(id); n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		i = len(e.extracted)
+		e.indices[lic.Name] = i
+		e.extracted = append(e.extracted, ExtractedLicensingInfo{LicenseID: id, Name: lic.Name})
+	}
+	if e.extracted[i].ExtractedText == "" {
+		e.extracted[i].ExtractedText = lic.Content
+	}
+	return e.extracted[i].LicenseID
+}
+
+func (e *extractedLicenses) allocated(id string) bool {
+	for _, info := range e.extracted {
+		if info.LicenseID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// infos returns the extracted licenses, with NOASSERTION as the text of those not found in any license file (i.e. set
+// by overrides)
+func (e *extractedLicenses) infos() []ExtractedLicensingInfo {
+	for i := range e.extracted {
+		if e.extracted[i].ExtractedText == "" {
+			e.extracted[i].ExtractedText = noAssertion
+		}
+	}
+	return e.extracted
+}
+
+// idAllocator allocates unique SPDX identifiers, which may only contain letters, numbers, "." and "-"
+type idAllocator map[string]bool
+
+func (a idAllocator) allocate(name string) string {
+	base := "SPDXRef-" + strings.Trim(idRgx.ReplaceAllString(name, "-"), "-")
+	id := base
+	for i := 2; a[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	a[id] = true
+	return id
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+package spdx_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+	"github.com/uw-labs/lichen/internal/spdx"
+)
+
+var (
+	foo = model.BuildInfo{Path: "/bin/foo", PackagePath: "github.com/acme/foo/cmd/foo", ModulePath: "github.com/acme/foo", ModuleVersion: "v1.0.0"}
+	bar = model.BuildInfo{Path: "/bin/bar", PackagePath: "github.com/acme/bar", ModulePath: "github.com/acme/bar", ModuleVersion: "(devel)"}
+)
+
+func TestCombined(t *testing.T) {
+	testCases := []struct {
+		name         string
+		binary       model.BuildInfo // the binary using the module, foo if unset
+		module       scan.EvaluatedModule
+		expected     spdx.Package
+		extracted    []spdx.ExtractedLicensingInfo
+		relationship string // of the binary to the module, DEPENDS_ON if unset
+	}{
+		{
+			name: "detected licenses",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.2.0+incompatible"},
+					Sum:             "h1:U+s90UTSYgptZMwQh2aRr3LuazLJIa+Pg3Kc1ylSYVY=",
+					ZipSHA256:       "53eb3dd144d2620a6d64cc10876691af72ee6b32c921af8f83729cd729526156",
+					Licenses: []model.License{
+						{Name: "MIT", Path: "/mod/LICENSE", Content: "MIT License\n\nCopyright (c) 2020 Abc Xyz\n\nPermission is hereby granted..."},
+						{Name: "Apache-2.0", Path: "/mod/LICENSE.apache", Content: "Apache License\n\ncopyright notice that is included in or attached to the work"},
+					},
+				},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-abc-xyz-v1.2.0-incompatible",
+				Name:             "github.com/abc/xyz",
+				VersionInfo:      "v1.2.0+incompatible",
+				DownloadLocation: "https://proxy.golang.org/github.com/abc/xyz/@v/v1.2.0+incompatible.zip",
+				Checksums: []spdx.Checksum{
+					{Algorithm: "SHA256", ChecksumValue: "53eb3dd144d2620a6d64cc10876691af72ee6b32c921af8f83729cd729526156"},
+				},
+				LicenseConcluded: "(Apache-2.0 AND MIT)",
+				LicenseDeclared:  "(Apache-2.0 AND MIT)",
+				CopyrightText:    "Copyright (c) 2020 Abc Xyz",
+				Comment:          "lichen decision: allowed",
+				ExternalRefs: []spdx.ExternalRef{
+					{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/abc/xyz@v1.2.0%2Bincompatible"},
+				},
+			},
+		},
+		{
+			name: "licenses set by override are concluded, and those they replaced declared",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/overridden/mod", Version: "v0.1.0"},
+					Licenses:        []model.License{{Name: "BSD-3-Clause", Confidence: 1}},
+				},
+				Decision: scan.DecisionAllowed,
+				Overridden: []model.License{
+					{Name: "BSD-2-Clause", Path: "/mod/LICENSE", Content: "Copyright (c) 2019 Overridden\n\nRedistribution and use...", Confidence: 0.7},
+				},
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-overridden-mod-v0.1.0",
+				Name:             "github.com/overridden/mod",
+				VersionInfo:      "v0.1.0",
+				DownloadLocation: "https://proxy.golang.org/github.com/overridden/mod/@v/v0.1.0.zip",
+				LicenseConcluded: "BSD-3-Clause",
+				LicenseDeclared:  "BSD-2-Clause",
+				CopyrightText:    "Copyright (c) 2019 Overridden",
+				Comment:          "lichen decision: allowed",
+				ExternalRefs: []spdx.ExternalRef{
+					{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/overridden/mod@v0.1.0"},
+				},
+			},
+		},
+		{
+			name: "licenses set by override, without license files",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/overridden/mod", Version: "v0.1.0"},
+					Licenses:        []model.License{{Name: "BSD-3-Clause", Confidence: 1}},
+				},
+				Decision: scan.DecisionNotAllowedLicenseNotPermitted,
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-overridden-mod-v0.1.0",
+				Name:             "github.com/overridden/mod",
+				VersionInfo:      "v0.1.0",
+				DownloadLocation: "https://proxy.golang.org/github.com/overridden/mod/@v/v0.1.0.zip",
+				LicenseConcluded: "BSD-3-Clause",
+				LicenseDeclared:  "NOASSERTION",
+				CopyrightText:    "NOASSERTION",
+				Comment:          "lichen decision: licenses-not-allowed",
+				ExternalRefs: []spdx.ExternalRef{
+					{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/overridden/mod@v0.1.0"},
+				},
+			},
+		},
+		{
+			name: "licenses not on the SPDX license list",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/facebook/mod", Version: "v1.0.0"},
+					Licenses: []model.License{
+						{Name: "Facebook-3-Clause", Path: "/mod/LICENSE", Content: "Copyright (c) Facebook, Inc.\n\nRedistribution and use..."},
+						{Name: "Acme Commercial", Confidence: 1},
+						{Name: "LicenseRef-Acme", Confidence: 1},
+					},
+				},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-facebook-mod-v1.0.0",
+				Name:             "github.com/facebook/mod",
+				VersionInfo:      "v1.0.0",
+				DownloadLocation: "https://proxy.golang.org/github.com/facebook/mod/@v/v1.0.0.zip",
+				LicenseConcluded: "(LicenseRef-Acme AND LicenseRef-Acme-Commercial AND LicenseRef-Facebook-3-Clause)",
+				LicenseDeclared:  "LicenseRef-Facebook-3-Clause",
+				CopyrightText:    "Copyright (c) Facebook, Inc.",
+				Comment:          "lichen decision: allowed",
+				ExternalRefs: []spdx.ExternalRef{
+					{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/facebook/mod@v1.0.0"},
+				},
+			},
+			extracted: []spdx.ExtractedLicensingInfo{
+				{LicenseID: "LicenseRef-Facebook-3-Clause", ExtractedText: "Copyright (c) Facebook, Inc.\n\nRedistribution and use...", Name: "Facebook-3-Clause"},
+				{LicenseID: "LicenseRef-Acme-Commercial", ExtractedText: "NOASSERTION", Name: "Acme Commercial"},
+				{LicenseID: "LicenseRef-Acme", ExtractedText: "NOASSERTION", Name: "LicenseRef-Acme"},
+			},
+		},
+		{
+			name:   "unversioned main module",
+			binary: bar,
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/acme/bar", Version: "(devel)"},
+					Main:            true,
+				},
+				Decision: scan.DecisionNotAllowedNoLicenseFiles,
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-acme-bar-devel",
+				Name:             "github.com/acme/bar",
+				VersionInfo:      "(devel)",
+				DownloadLocation: "NOASSERTION",
+				LicenseConcluded: "NOASSERTION",
+				LicenseDeclared:  "NOASSERTION",
+				CopyrightText:    "NOASSERTION",
+				Comment:          "lichen decision: no-license-files",
+			},
+			relationship: "CONTAINS",
+		},
+		{
+			name: "another version of the main module",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/acme/foo", Version: "v0.9.0"},
+					Licenses:        []model.License{{Name: "MIT", Path: "/foo/LICENSE", Content: "MIT License"}},
+					Main:            true,
+				},
+				Decision: scan.DecisionAllowed,
+			},
+			expected: spdx.Package{
+				SPDXID:           "SPDXRef-Module-github.com-acme-foo-v0.9.0",
+				Name:             "github.com/acme/foo",
+				VersionInfo:      "v0.9.0",
+				DownloadLocation: "https://proxy.golang.org/github.com/acme/foo/@v/v0.9.0.zip",
+				LicenseConcluded: "MIT",
+				LicenseDeclared:  "MIT",
+				CopyrightText:    "NOASSERTION",
+				Comment:          "lichen decision: allowed",
+				ExternalRefs: []spdx.ExternalRef{
+					{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/acme/foo@v0.9.0"},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			bin, relationship := tc.binary, tc.relationship
+			if bin.Path == "" {
+				bin = foo
+			}
+			if relationship == "" {
+				relationship = "DEPENDS_ON"
+			}
+			m := tc.module
+			m.UsedBy = []string{bin.Path}
+			doc, err := spdx.Combined(scan.Summary{Binaries: []model.BuildInfo{bin}, Modules: []scan.EvaluatedModule{m}}, time.Now())
+			require.NoError(t, err)
+
+			require.Len(t, doc.Packages, 2)
+			assert.Equal(t, tc.expected, doc.Packages[1])
+			assert.Equal(t, tc.extracted, doc.HasExtractedLicensingInfos)
+			binID := doc.Packages[0].SPDXID
+			assert.Equal(t, []spdx.Relationship{
+				{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: binID},
+				{SPDXElementID: binID, RelationshipType: relationship, RelatedSPDXElement: tc.expected.SPDXID},
+			}, doc.Relationships)
+		})
+	}
+}
+
+func TestCombined_Document(t *testing.T) {
+	doc, err := spdx.Combined(scan.Summary{Binaries: []model.BuildInfo{foo, bar}}, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "lichen", doc.Name)
+	assert.True(t, strings.HasPrefix(doc.DocumentNamespace, "https://spdx.org/spdxdocs/lichen-lichen-"))
+	assert.Equal(t, "2023-06-01T12:00:00Z", doc.CreationInfo.Created)
+	assert.Equal(t, []spdx.Package{
+		{
+			SPDXID:           "SPDXRef-Binary-foo",
+			Name:             "foo",
+			VersionInfo:      "v1.0.0",
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			Comment:          "built from package github.com/acme/foo/cmd/foo",
+		},
+		{
+			SPDXID:           "SPDXRef-Binary-bar",
+			Name:             "bar",
+			VersionInfo:      "(devel)",
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			CopyrightText:    "NOASSERTION",
+			Comment:          "built from package github.com/acme/bar",
+		},
+	}, doc.Packages)
+}
+
+func TestPerBinary(t *testing.T) {
+	module := func(path string, usedBy ...string) scan.EvaluatedModule {
+		return scan.EvaluatedModule{
+			Module: model.Module{
+				ModuleReference: model.ModuleReference{Path: path, Version: "v1.0.0"},
+				Licenses:        []model.License{{Name: "Acme Commercial", Confidence: 1}},
+			},
+			Decision: scan.DecisionAllowed,
+			UsedBy:   usedBy,
+		}
+	}
+	testCases := []struct {
+		name      string
+		binaries  []model.BuildInfo
+		modules   []scan.EvaluatedModule
+		expected  [][]string // names of the packages in each document
+		extracted []int      // number of extracted licenses in each document
+	}{
+		{
+			name:     "modules only described alongside the binaries using them",
+			binaries: []model.BuildInfo{foo, bar},
+			modules: []scan.EvaluatedModule{
+				module("github.com/shared/mod", foo.Path, bar.Path),
+				module("github.com/bar/only", bar.Path),
+			},
+			expected: [][]string{
+				{"foo", "github.com/shared/mod"},
+				{"bar", "github.com/shared/mod", "github.com/bar/only"},
+			},
+			extracted: []int{1, 1},
+		},
+		{
+			name:      "binary using no modules",
+			binaries:  []model.BuildInfo{foo, bar},
+			modules:   []scan.EvaluatedModule{module("github.com/bar/only", bar.Path)},
+			expected:  [][]string{{"foo"}, {"bar", "github.com/bar/only"}},
+			extracted: []int{0, 1},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			docs, err := spdx.PerBinary(scan.Summary{Binaries: tc.binaries, Modules: tc.modules}, time.Now())
+			require.NoError(t, err)
+			require.Len(t, docs, len(tc.expected))
+
+			namespaces := make(map[string]bool)
+			for i, doc := range docs {
+				assert.Equal(t, tc.expected[i][0], doc.Name)
+				var names []string
+				for _, p := range doc.Packages {
+					names = append(names, p.Name)
+				}
+				assert.Equal(t, tc.expected[i], names)
+				assert.Len(t, doc.HasExtractedLicensingInfos, tc.extracted[i])
+				assert.False(t, namespaces[doc.DocumentNamespace], "namespace %s is not unique", doc.DocumentNamespace)
+				namespaces[doc.DocumentNamespace] = true
+			}
+		})
+	}
+}
+
+func TestWriteTagValue(t *testing.T) {
+	doc := spdx.Document{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "foo",
+		DocumentNamespace: "https://spdx.org/spdxdocs/lichen-foo-1",
+		CreationInfo:      spdx.CreationInfo{Created: "2023-06-01T12:00:00Z", Creators: []string{"Tool: lichen"}},
+		Packages: []spdx.Package{{
+			SPDXID:           "SPDXRef-Module-github.com-abc-xyz-v1.0.0",
+			Name:             "github.com/abc/xyz",
+			VersionInfo:      "v1.0.0",
+			DownloadLocation: "NOASSERTION",
+			Checksums:        []spdx.Checksum{{Algorithm: "SHA256", ChecksumValue: "abc123"}},
+			LicenseConcluded: "(LicenseRef-Acme AND MIT)",
+			LicenseDeclared:  "MIT",
+			CopyrightText:    "Copyright 2020 A\nCopyright 2021 B",
+			ExternalRefs:     []spdx.ExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/abc/xyz@v1.0.0"}},
+		}},
+		Relationships: []spdx.Relationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Module-github.com-abc-xyz-v1.0.0"},
+		},
+		HasExtractedLicensingInfos: []spdx.ExtractedLicensingInfo{
+			{LicenseID: "LicenseRef-Acme", ExtractedText: "Acme License\n\nAll rights reserved.", Name: "Acme"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, spdx.WriteTagValue(&buf, doc))
+	assert.Equal(t, `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: foo
+DocumentNamespace: https://spdx.org/spdxdocs/lichen-foo-1
+Creator: Tool: lichen
+Created: 2023-06-01T12:00:00Z
+
+PackageName: github.com/abc/xyz
+SPDXID: SPDXRef-Module-github.com-abc-xyz-v1.0.0
+PackageVersion: v1.0.0
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: false
+PackageChecksum: SHA256: abc123
+PackageLicenseConcluded: (LicenseRef-Acme AND MIT)
+PackageLicenseDeclared: MIT
+PackageCopyrightText: <text>Copyright 2020 A
+Copyright 2021 B</text>
+ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/abc/xyz@v1.0.0
+
+LicenseID: LicenseRef-Acme
+ExtractedText: <text>Acme License
+
+All rights reserved.</text>
+LicenseName: Acme
+
+Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Module-github.com-abc-xyz-v1.0.0
+`, buf.String())
+}
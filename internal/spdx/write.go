@@ -0,0 +1,85 @@
+package spdx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON writes the document in the SPDX JSON format
+func WriteJSON(w io.Writer, doc Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteTagValue writes the document in the SPDX tag-value format
+func WriteTagValue(w io.Writer, doc Document) error {
+	tw := &tagWriter{w: w}
+	tw.tag("SPDXVersion", doc.SPDXVersion)
+	tw.tag("DataLicense", doc.DataLicense)
+	tw.tag("SPDXID", doc.SPDXID)
+	tw.tag("DocumentName", doc.Name)
+	tw.tag("DocumentNamespace", doc.DocumentNamespace)
+	for _, c := range doc.CreationInfo.Creators {
+		tw.tag("Creator", c)
+	}
+	tw.tag("Created", doc.CreationInfo.Created)
+	for _, p := range doc.Packages {
+		tw.line("")
+		tw.tag("PackageName", p.Name)
+		tw.tag("SPDXID", p.SPDXID)
+		if p.VersionInfo != "" {
+			tw.tag("PackageVersion", p.VersionInfo)
+		}
+		tw.tag("PackageDownloadLocation", p.DownloadLocation)
+		tw.tag("FilesAnalyzed", fmt.Sprint(p.FilesAnalyzed))
+		for _, c := range p.Checksums {
+			tw.tag("PackageChecksum", fmt.Sprintf("%s: %s", c.Algorithm, c.ChecksumValue))
+		}
+		tw.tag("PackageLicenseConcluded", p.LicenseConcluded)
+		tw.tag("PackageLicenseDeclared", p.LicenseDeclared)
+		tw.tag("PackageCopyrightText", p.CopyrightText)
+		if p.Comment != "" {
+			tw.tag("PackageComment", p.Comment)
+		}
+		for _, ref := range p.ExternalRefs {
+			tw.tag("ExternalRef", fmt.Sprintf("%s %s %s", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator))
+		}
+	}
+	for _, info := range doc.HasExtractedLicensingInfos {
+		tw.line("")
+		tw.tag("LicenseID", info.LicenseID)
+		tw.tag("ExtractedText", info.ExtractedText)
+		tw.tag("LicenseName", info.Name)
+	}
+	if len(doc.Relationships) > 0 {
+		tw.line("")
+	}
+	for _, r := range doc.Relationships {
+		tw.tag("Relationship", fmt.Sprintf("%s %s %s", r.SPDXElementID, r.RelationshipType, r.RelatedSPDXElement))
+	}
+	return tw.err
+}
+
+// tagWriter writes tag-value pairs, retaining the first error encountered
+type tagWriter struct {
+	w   io.Writer
+	err error
+}
+
+// tag writes a tag-value pair, wrapping values that span multiple lines in <text> tags
+func (t *tagWriter) tag(tag, value string) {
+	if strings.Contains(value, "\n") {
+		value = "<text>" + value + "</text>"
+	}
+	t.line(tag + ": " + value)
+}
+
+func (t *tagWriter) line(s string) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintln(t.w, s)
+}
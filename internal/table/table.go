@@ -0,0 +1,133 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// Column is a column of the results table
+type Column string
+
+const (
+	ColumnModule      Column = "module"
+	ColumnVersion     Column = "version"
+	ColumnLicenses    Column = "licenses"
+	ColumnConfidence  Column = "confidence"
+	ColumnDecision    Column = "decision"
+	ColumnExplanation Column = "explanation"
+	ColumnUsedBy      Column = "used-by"
+)
+
+// DefaultColumns are the columns included when none are specified, in order
+var DefaultColumns = []Column{
+	ColumnModule,
+	ColumnVersion,
+	ColumnLicenses,
+	ColumnConfidence,
+	ColumnDecision,
+	ColumnExplanation,
+	ColumnUsedBy,
+}
+
+// ParseColumns parses a comma separated list of columns, e.g. "module,version,decision"
+func ParseColumns(s string) ([]Column, error) {
+	var columns []Column
+	for _, name := range strings.Split(s, ",") {
+		c := Column(strings.TrimSpace(name))
+		if !c.valid() {
+			names := make([]string, 0, len(DefaultColumns))
+			for _, d := range DefaultColumns {
+				names = append(names, string(d))
+			}
+			return nil, fmt.Errorf("unrecognised column %q (expected one of: %s)", c, strings.Join(names, ", "))
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+func (c Column) valid() bool {
+	for _, d := range DefaultColumns {
+		if c == d {
+			return true
+		}
+	}
+	return false
+}
+
+// header returns the title of the column
+func (c Column) header() string {
+	switch c {
+	case ColumnUsedBy:
+		return "Used By"
+	default:
+		return strings.ToUpper(string(c[:1])) + string(c[1:])
+	}
+}
+
+// value returns the value of the column for the module
+func (c Column) value(m scan.EvaluatedModule) string {
+	switch c {
+	case ColumnModule:
+		return m.Path
+	case ColumnVersion:
+		return m.Version
+	case ColumnLicenses:
+		names, _ := licenses(m)
+		return strings.Join(names, ", ")
+	case ColumnConfidence:
+		_, confidences := licenses(m)
+		values := make([]string, 0, len(confidences))
+		for _, conf := range confidences {
+			values = append(values, strconv.FormatFloat(conf, 'f', 2, 64))
+		}
+		return strings.Join(values, ", ")
+	case ColumnDecision:
+		text, _ := m.Decision.MarshalText()
+		return string(text)
+	case ColumnExplanation:
+		return m.ExplainDecision()
+	case ColumnUsedBy:
+		return strings.Join(m.UsedBy, ", ")
+	default:
+		return ""
+	}
+}
+
+// licenses returns the unique names of the module's licenses, along with the highest confidence of each
+func licenses(m scan.EvaluatedModule) (names []string, confidences []float64) {
+	index := make(map[string]int, len(m.Licenses))
+	for _, lic := range m.Licenses {
+		if i, found := index[lic.Name]; found {
+			if lic.Confidence > confidences[i] {
+				confidences[i] = lic.Confidence
+			}
+			continue
+		}
+		index[lic.Name] = len(names)
+		names = append(names, lic.Name)
+		confidences = append(confidences, lic.Confidence)
+	}
+	return names, confidences
+}
+
+// rows returns the header row, followed by a row for each module
+func rows(summary scan.Summary, columns []Column) [][]string {
+	rows := make([][]string, 0, len(summary.Modules)+1)
+	header := make([]string, 0, len(columns))
+	for _, c := range columns {
+		header = append(header, c.header())
+	}
+	rows = append(rows, header)
+	for _, m := range summary.Modules {
+		row := make([]string, 0, len(columns))
+		for _, c := range columns {
+			row = append(row, c.value(m))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
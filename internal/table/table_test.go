@@ -0,0 +1,153 @@
+package table_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+	"github.com/uw-labs/lichen/internal/table"
+)
+
+func TestParseColumns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected []table.Column
+		err      string
+	}{
+		{
+			name:     "all columns",
+			input:    "module,version,licenses,confidence,decision,explanation,used-by",
+			expected: table.DefaultColumns,
+		},
+		{
+			name:     "subset with spaces",
+			input:    "decision, module",
+			expected: []table.Column{table.ColumnDecision, table.ColumnModule},
+		},
+		{
+			name:  "unrecognised column",
+			input: "module,owner",
+			err:   `unrecognised column "owner" (expected one of: module, version, licenses, confidence, decision, explanation, used-by)`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			columns, err := table.ParseColumns(tc.input)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, columns)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	ref := model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"}
+	testCases := []struct {
+		name     string
+		module   scan.EvaluatedModule
+		columns  []table.Column
+		csv      string // the row written as CSV
+		markdown string // the row written as Markdown
+	}{
+		{
+			name: "highest confidence of each license",
+			module: scan.EvaluatedModule{
+				Module: model.Module{
+					ModuleReference: ref,
+					Licenses: []model.License{
+						{Name: "MIT", Path: "/xyz/LICENSE", Confidence: 0.95},
+						{Name: "Apache-2.0", Path: "/xyz/LICENSE.apache", Confidence: 1},
+						{Name: "MIT", Path: "/xyz/LICENSE.mit", Confidence: 0.98},
+					},
+				},
+				Decision: scan.DecisionAllowed,
+				UsedBy:   []string{"/bin/foo", "/bin/bar"},
+			},
+			columns:  table.DefaultColumns,
+			csv:      `github.com/abc/xyz,v1.0.0,"MIT, Apache-2.0","0.98, 1.00",allowed,allowed,"/bin/foo, /bin/bar"`,
+			markdown: `| github.com/abc/xyz | v1.0.0 | MIT, Apache-2.0 | 0.98, 1.00 | allowed | allowed | /bin/foo, /bin/bar |`,
+		},
+		{
+			name: "licenses set by override",
+			module: scan.EvaluatedModule{
+				Module:     model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "Acme \"Commercial\"", Confidence: 1}}},
+				Decision:   scan.DecisionAllowed,
+				Rules:      []scan.Rule{{Type: scan.RuleTypeOverride, Path: "github.com/abc/xyz"}},
+				Overridden: []model.License{{Name: "MIT", Path: "/xyz/LICENSE", Confidence: 0.5}},
+			},
+			columns:  []table.Column{table.ColumnLicenses, table.ColumnConfidence},
+			csv:      `"Acme ""Commercial""",1.00`,
+			markdown: `| Acme "Commercial" | 1.00 |`,
+		},
+		{
+			name: "overall decision of policies scoped to binaries",
+			module: scan.EvaluatedModule{
+				Module:       model.Module{ModuleReference: ref, Licenses: []model.License{{Name: "GPL-3.0", Path: "/xyz/LICENSE", Confidence: 1}}},
+				Decision:     scan.DecisionNotAllowedLicenseNotPermitted,
+				NotPermitted: []string{"GPL-3.0"},
+				Severity:     scan.SeverityError,
+				Binaries: []scan.BinaryDecision{
+					{Binary: "/bin/tool", Policy: "tools", Decision: scan.DecisionAllowed},
+					{Binary: "/bin/server", Decision: scan.DecisionNotAllowedLicenseNotPermitted, NotPermitted: []string{"GPL-3.0"}, Severity: scan.SeverityError},
+				},
+				UsedBy: []string{"/bin/tool", "/bin/server"},
+			},
+			columns:  []table.Column{table.ColumnDecision, table.ColumnExplanation, table.ColumnUsedBy},
+			csv:      `licenses-not-allowed,not allowed - non-permitted licenses: [GPL-3.0],"/bin/tool, /bin/server"`,
+			markdown: `| licenses-not-allowed | not allowed - non-permitted licenses: [GPL-3.0] | /bin/tool, /bin/server |`,
+		},
+		{
+			name: "baselined, with a justification needing escaping",
+			module: scan.EvaluatedModule{
+				Module:    model.Module{ModuleReference: ref},
+				Decision:  scan.DecisionNotAllowedModuleDenied,
+				Severity:  scan.SeverityError,
+				Baselined: true,
+				Rules:     []scan.Rule{{Type: scan.RuleTypeModuleDeny, Path: "github.com/abc/*", Metadata: scan.Metadata{Justification: "abandoned | unsafe,\nsee issue"}}},
+			},
+			columns:  []table.Column{table.ColumnModule, table.ColumnLicenses, table.ColumnExplanation},
+			csv:      "github.com/abc/xyz,,\"not allowed - module denied by rule for github.com/abc/* (abandoned | unsafe,\nsee issue)\"",
+			markdown: `| github.com/abc/xyz |  | not allowed - module denied by rule for github.com/abc/* (abandoned \| unsafe, see issue) |`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			summary := scan.Summary{Modules: []scan.EvaluatedModule{tc.module}}
+
+			var csv bytes.Buffer
+			require.NoError(t, table.WriteCSV(&csv, summary, tc.columns))
+			lines := strings.SplitN(csv.String(), "\n", 2)
+			require.Len(t, lines, 2)
+			assert.Equal(t, tc.csv+"\n", lines[1])
+
+			var markdown bytes.Buffer
+			require.NoError(t, table.WriteMarkdown(&markdown, summary, tc.columns))
+			lines = strings.Split(strings.TrimSuffix(markdown.String(), "\n"), "\n")
+			require.Len(t, lines, 3)
+			assert.Equal(t, tc.markdown, lines[2])
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteCSV(&buf, scan.Summary{}, table.DefaultColumns))
+	assert.Equal(t, "Module,Version,Licenses,Confidence,Decision,Explanation,Used By\n", buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteMarkdown(&buf, scan.Summary{}, []table.Column{table.ColumnModule, table.ColumnLicenses, table.ColumnDecision}))
+	assert.Equal(t, "| Module | Licenses | Decision |\n| --- | --- | --- |\n", buf.String())
+}
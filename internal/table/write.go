@@ -0,0 +1,35 @@
+package table
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// WriteCSV writes the results as CSV, with a header row followed by a row for each module
+func WriteCSV(w io.Writer, summary scan.Summary, columns []Column) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows(summary, columns)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the results as a Markdown table, suitable for pull request comments
+func WriteMarkdown(w io.Writer, summary scan.Summary, columns []Column) error {
+	var b strings.Builder
+	for i, row := range rows(summary, columns) {
+		for j, cell := range row {
+			row[j] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+		if i == 0 {
+			fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(row)))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
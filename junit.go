@@ -0,0 +1,17 @@
+package main
+
+import (
+	"io"
+
+	"github.com/uw-labs/lichen/internal/junit"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// writeJUnit writes a JUnit XML report, with a test suite for each binary and a test case for each module
+func writeJUnit(path string, summary scan.Summary) error {
+	report, err := junit.New(summary)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error { return junit.WriteXML(w, report) })
+}
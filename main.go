@@ -1,10 +1,9 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -13,36 +12,131 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/muesli/termenv"
 	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/baseline"
+	"github.com/uw-labs/lichen/internal/results"
 	"github.com/uw-labs/lichen/internal/scan"
-	"gopkg.in/yaml.v2"
 )
 
 const tmpl = `{{range .Modules}}
-{{- .Module}}: {{range $i, $_ := .Module.Licenses}}{{if $i}}, {{end}}{{.Name}}{{end}} 
-{{- if .Allowed}} ({{ Color "#00ff00" .ExplainDecision}}){{else}} ({{ Color "#ff0000" .ExplainDecision}}){{end}}
-{{end}}`
+{{- .Module}}{{if .Main}} (main){{end}}: {{range $i, $_ := .Module.Licenses}}{{if $i}}, {{end}}{{.Name}}{{end}} 
+{{- if .Allowed}} ({{ Color "#00ff00" .ExplainDecision}}){{else if .Failed}} ({{ Color "#ff0000" .ExplainDecision}}){{else}} ({{ Color "#ffff00" .ExplainDecision}}){{end}}
+{{- range .Binaries}}{{if not .Allowed}} [not allowed for {{.Binary}}{{with .Policy}} by policy {{.}}{{end}}]{{end}}{{end}}
+{{- range .Rules}} [{{.Type}} {{.Path}}{{with .Justification}}: {{.}}{{end}}]{{end}}
+{{- range .Notices}} [{{.Severity}}: {{.Message}}]{{end}}
+{{- if .Baselined}} [baselined]{{end}}
+{{end}}
+{{- range .StaleRules}}
+{{- if eq .Severity.String "error"}}{{ Color "#ff0000" "stale" }}{{else}}{{ Color "#ffff00" "stale" }}{{end}} {{.Type}}{{with .Policy}} (policy {{.}}){{end}} {{.Path}}: {{.Reason}}
+{{end}}
+{{- range .Comparisons}}{{if not .Matches}}
+{{- Color "#ffff00" "mismatch"}} {{.Module}} in {{.SBOM}}: {{.Explain}}
+{{end}}{{end}}`
+
+// flags are the flags of the scan run by the app's default action
+var flags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "config",
+		Aliases: []string{"c"},
+		Usage:   "path to config file",
+	},
+	&cli.StringFlag{
+		Name:    "template",
+		Aliases: []string{"t"},
+		Usage:   "template for writing out each module and resolved licenses",
+		Value:   tmpl,
+	},
+	&cli.StringSliceFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "write results in the supplied format to the supplied path (format=path, with - for stdout), can be repeated",
+	},
+	&cli.StringFlag{
+		Name:    "format",
+		Aliases: []string{"f"},
+		Usage:   "format of the results written to stdout, unless written by --output (text uses --template)",
+		Value:   "text",
+	},
+	&cli.StringFlag{
+		Name:  "columns",
+		Usage: "comma separated columns of csv and markdown tables (module, version, licenses, confidence, decision, explanation, used-by)",
+		Value: "module,version,licenses,confidence,decision,explanation,used-by",
+	},
+	&cli.StringFlag{
+		Name:    "json",
+		Aliases: []string{"j"},
+		Usage:   "write JSON results to the supplied file",
+	},
+	&cli.StringFlag{
+		Name:  "spdx",
+		Usage: "deprecated, use --output spdx=path (or spdx-json, spdx-per-binary, spdx-json-per-binary) instead",
+	},
+	&cli.StringFlag{
+		Name:  "spdx-format",
+		Usage: "deprecated, format of SPDX documents written via --spdx (tag-value or json)",
+		Value: "tag-value",
+	},
+	&cli.BoolFlag{
+		Name:  "spdx-per-binary",
+		Usage: "deprecated, write an SPDX document per binary to the directory supplied via --spdx",
+	},
+	&cli.StringFlag{
+		Name:  "cyclonedx",
+		Usage: "deprecated, use --output cyclonedx=path (or cyclonedx-xml, cyclonedx-per-binary, cyclonedx-xml-per-binary) instead",
+	},
+	&cli.StringFlag{
+		Name:  "cyclonedx-format",
+		Usage: "deprecated, format of CycloneDX BOMs written via --cyclonedx (json or xml)",
+		Value: "json",
+	},
+	&cli.BoolFlag{
+		Name:  "cyclonedx-per-binary",
+		Usage: "deprecated, write a CycloneDX BOM per binary to the directory supplied via --cyclonedx",
+	},
+	&cli.StringFlag{
+		Name:  "sarif",
+		Usage: "deprecated, use --output sarif=path instead",
+	},
+	&cli.StringFlag{
+		Name:  "html",
+		Usage: "deprecated, use --output html=path instead",
+	},
+	&cli.StringFlag{
+		Name:  "junit",
+		Usage: "deprecated, use --output junit=path instead",
+	},
+	&cli.StringFlag{
+		Name:  "sarif-module-root",
+		Usage: "root directory of the module whose go.mod require lines SARIF results are located at",
+	},
+	&cli.StringSliceFlag{
+		Name:  "sbom",
+		Usage: "SBOM (CycloneDX or SPDX) listing modules to scan in place of a binary, can be repeated",
+	},
+	&cli.BoolFlag{
+		Name:  "main-module",
+		Usage: "include the main module of each binary - versioned main modules are fetched from the module cache, and others are located via --source",
+	},
+	&cli.StringSliceFlag{
+		Name:  "source",
+		Usage: "source directory of a main module built from a source checkout, for use with --main-module (can be repeated)",
+	},
+	&cli.StringFlag{
+		Name:    "baseline",
+		Aliases: []string{"b"},
+		Usage:   "path to a baseline of accepted failures - only failures not in the baseline cause lichen to fail",
+	},
+}
 
 func main() {
 	a := &cli.App{
 		Name:  "lichen",
 		Usage: "evaluate module dependencies from go compiled binaries",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "config",
-				Aliases: []string{"c"},
-				Usage:   "path to config file",
-			},
-			&cli.StringFlag{
-				Name:    "template",
-				Aliases: []string{"t"},
-				Usage:   "template for writing out each module and resolved licenses",
-				Value:   tmpl,
-			},
-			&cli.StringFlag{
-				Name:    "json",
-				Aliases: []string{"j"},
-				Usage:   "write JSON results to the supplied file",
-			},
+		Flags: flags,
+		Commands: []*cli.Command{
+			configCommand,
+			baselineCommand,
+			diffCommand,
+			noticesCommand,
 		},
 		Action: run,
 	}
@@ -53,9 +147,9 @@ func main() {
 }
 
 func run(c *cli.Context) error {
-	if c.NArg() == 0 {
+	if c.NArg() == 0 && len(c.StringSlice("sbom")) == 0 {
 		_ = cli.ShowAppHelp(c)
-		return errors.New("path to at least one binary must be supplied")
+		return errors.New("path to at least one binary or SBOM must be supplied")
 	}
 
 	f := termenv.TemplateFuncs(termenv.ColorProfile())
@@ -64,6 +158,15 @@ func run(c *cli.Context) error {
 		return err
 	}
 
+	formats, err := outputFormats(c, output)
+	if err != nil {
+		return err
+	}
+	outputs, err := parseOutputs(c, formats)
+	if err != nil {
+		return err
+	}
+
 	conf, err := parseConfig(c.String("config"))
 	if err != nil {
 		return fmt.Errorf("invalid config: %w", err)
@@ -74,42 +177,58 @@ func run(c *cli.Context) error {
 		return fmt.Errorf("invalid arguments: %w", err)
 	}
 
-	summary, err := scan.Run(c.Context, conf, paths...)
+	sourceDirs, err := absolutePaths(c.StringSlice("source"))
+	if err != nil {
+		return fmt.Errorf("invalid source directories: %w", err)
+	}
+
+	sboms, err := absolutePaths(c.StringSlice("sbom"))
+	if err != nil {
+		return fmt.Errorf("invalid SBOMs: %w", err)
+	}
+
+	opts := scan.Options{
+		MainModule: c.Bool("main-module"),
+		SourceDirs: sourceDirs,
+		SBOMs:      sboms,
+	}
+	summary, err := scan.Run(c.Context, conf, opts, paths...)
 	if err != nil {
 		return fmt.Errorf("failed to evaluate licenses: %w", err)
 	}
 
-	if jsonPath := c.String("json"); jsonPath != "" {
-		if err := writeJSON(jsonPath, summary); err != nil {
-			return fmt.Errorf("failed to write json: %w", err)
+	var unneeded []baseline.Entry
+	if baselinePath := c.String("baseline"); baselinePath != "" {
+		b, err := baseline.Read(baselinePath)
+		if err != nil {
+			return fmt.Errorf("invalid baseline: %w", err)
 		}
+		unneeded = b.Apply(&summary)
 	}
 
-	if err := output.Execute(os.Stdout, summary); err != nil {
-		return fmt.Errorf("failed to write results: %w", err)
+	if err := writeOutputs(outputs, formats, summary); err != nil {
+		return err
+	}
+	for _, e := range unneeded {
+		w := os.Stdout
+		if !textOnStdout(outputs) {
+			w = os.Stderr
+		}
+		fmt.Fprintf(w, "baseline entry for %s (%s) is no longer needed\n", e.Path, explainEntry(e))
 	}
 
 	var rErr error
 	for _, m := range summary.Modules {
-		if !m.Allowed() {
-			rErr = multierror.Append(rErr, fmt.Errorf("%s: %s", m.Module.ModuleReference, m.ExplainDecision()))
+		if m.Failed() {
+			rErr = multierror.Append(rErr, fmt.Errorf("%s: %s", m.Module.ModuleReference, m.ExplainFailure()))
 		}
 	}
-	return rErr
-}
-
-func parseConfig(path string) (scan.Config, error) {
-	var conf scan.Config
-	if path != "" {
-		b, err := ioutil.ReadFile(path)
-		if err != nil {
-			return scan.Config{}, fmt.Errorf("failed to read file %q: %w", path, err)
-		}
-		if err := yaml.Unmarshal(b, &conf); err != nil {
-			return scan.Config{}, fmt.Errorf("failed to parse yaml: %w", err)
+	for _, r := range summary.StaleRules {
+		if r.Severity == scan.SeverityError {
+			rErr = multierror.Append(rErr, fmt.Errorf("stale %s for %s: %s", r.Type, r.Path, r.Reason))
 		}
 	}
-	return conf, nil
+	return rErr
 }
 
 func absolutePaths(paths []string) ([]string, error) {
@@ -124,14 +243,29 @@ func absolutePaths(paths []string) ([]string, error) {
 	return mapped, nil
 }
 
+// writeJSON writes the results in the versioned schema published in schema/results.schema.json
 func writeJSON(path string, summary scan.Summary) error {
-	f, err := os.Create(path)
+	r, err := results.FromSummary(summary)
 	if err != nil {
-		return fmt.Errorf("failed to create file for json output: %w", err)
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error { return results.Write(w, r) })
+}
+
+// readJSON reads scan results previously written with the --json flag
+func readJSON(path string) (scan.Summary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return scan.Summary{}, fmt.Errorf("failed to open file %q: %w", path, err)
 	}
 	defer f.Close()
-	if err := json.NewEncoder(f).Encode(summary); err != nil {
-		return fmt.Errorf("json encode failed: %w", err)
+	r, err := results.Read(f)
+	if err != nil {
+		return scan.Summary{}, fmt.Errorf("failed to read results in %q: %w", path, err)
+	}
+	summary, err := r.Summary()
+	if err != nil {
+		return scan.Summary{}, fmt.Errorf("invalid results in %q: %w", path, err)
 	}
-	return nil
+	return summary, nil
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/notices"
+)
+
+var noticesCommand = &cli.Command{
+	Name:      "notices",
+	Usage:     "generate a third-party attribution document from the JSON results of a scan",
+	ArgsUsage: "path/to/results.json",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "output format (text, markdown or html)",
+			Value:   "text",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "write the document to the supplied file, rather than stdout",
+		},
+	},
+	Action: generateNotices,
+}
+
+func generateNotices(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return errors.New("path to a single JSON results file must be supplied")
+	}
+	var write func(io.Writer, notices.Document) error
+	switch format := c.String("format"); format {
+	case "text":
+		write = notices.WriteText
+	case "markdown":
+		write = notices.WriteMarkdown
+	case "html":
+		write = notices.WriteHTML
+	default:
+		return fmt.Errorf("unrecognised format %q (expected one of: text, markdown, html)", format)
+	}
+
+	summary, err := readJSON(c.Args().First())
+	if err != nil {
+		return err
+	}
+	doc := notices.New(summary)
+
+	if path := c.String("output"); path != "" {
+		return writeFile(path, func(w io.Writer) error { return write(w, doc) })
+	}
+	return write(os.Stdout, doc)
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/notices"
+	"github.com/uw-labs/lichen/internal/scan"
+	"github.com/uw-labs/lichen/internal/table"
+)
+
+// stdout is the path of outputs written to stdout
+const stdout = "-"
+
+// output is a destination for the scan results, in a particular format
+type output struct {
+	format string
+	path   string
+}
+
+// outputFormat writes the scan results to the supplied path
+type outputFormat func(path string, summary scan.Summary) error
+
+// outputFormats returns the formats results can be written in, keyed by name. Options particular to a format (e.g. the
+// columns of tables) are taken from its other flags.
+func outputFormats(c *cli.Context, tmpl *template.Template) (map[string]outputFormat, error) {
+	columns, err := table.ParseColumns(c.String("columns"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid columns: %w", err)
+	}
+	writeTable := func(write func(io.Writer, scan.Summary, []table.Column) error) outputFormat {
+		return func(path string, summary scan.Summary) error {
+			return writeFile(path, func(w io.Writer) error { return write(w, summary, columns) })
+		}
+	}
+	writeNotices := func(write func(io.Writer, notices.Document) error) outputFormat {
+		return func(path string, summary scan.Summary) error {
+			doc := notices.New(summary)
+			return writeFile(path, func(w io.Writer) error { return write(w, doc) })
+		}
+	}
+
+	writeSBOM := func(write func(path, format string, perBinary bool, summary scan.Summary) error, format string, perBinary bool) outputFormat {
+		return func(path string, summary scan.Summary) error {
+			return write(path, format, perBinary, summary)
+		}
+	}
+
+	return map[string]outputFormat{
+		"text": func(path string, summary scan.Summary) error {
+			return writeFile(path, func(w io.Writer) error { return tmpl.Execute(w, summary) })
+		},
+		"json":                     writeJSON,
+		"csv":                      writeTable(table.WriteCSV),
+		"markdown":                 writeTable(table.WriteMarkdown),
+		"spdx":                     writeSBOM(writeSPDX, "tag-value", false),
+		"spdx-json":                writeSBOM(writeSPDX, "json", false),
+		"spdx-per-binary":          writeSBOM(writeSPDX, "tag-value", true),
+		"spdx-json-per-binary":     writeSBOM(writeSPDX, "json", true),
+		"cyclonedx":                writeSBOM(writeCycloneDX, "json", false),
+		"cyclonedx-xml":            writeSBOM(writeCycloneDX, "xml", false),
+		"cyclonedx-per-binary":     writeSBOM(writeCycloneDX, "json", true),
+		"cyclonedx-xml-per-binary": writeSBOM(writeCycloneDX, "xml", true),
+		"sarif": func(path string, summary scan.Summary) error {
+			return writeSARIF(path, c.String("sarif-module-root"), summary)
+		},
+		"junit":            writeJUnit,
+		"html":             writeReport,
+		"notices":          writeNotices(notices.WriteText),
+		"notices-markdown": writeNotices(notices.WriteMarkdown),
+		"notices-html":     writeNotices(notices.WriteHTML),
+	}, nil
+}
+
+// parseOutputs returns the outputs requested via --output, along with those requested by the flags for individual
+// formats, and the results written to stdout unless another output is. Only one output can be written to stdout.
+func parseOutputs(c *cli.Context, formats map[string]outputFormat) ([]output, error) {
+	var outputs []output
+	for _, value := range c.StringSlice("output") {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid output %q (expected format=path)", value)
+		}
+		outputs = append(outputs, output{format: parts[0], path: parts[1]})
+	}
+
+	for _, name := range []string{"json", "spdx", "cyclonedx", "sarif", "junit", "html"} {
+		path := c.String(name)
+		if path == "" {
+			continue
+		}
+		format, err := flagFormat(c, name)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output{format: format, path: path})
+	}
+
+	var toStdout *output
+	for i, o := range outputs {
+		if _, found := formats[o.format]; !found {
+			return nil, fmt.Errorf("unrecognised output format %q (expected one of: %s)", o.format, formatNames(formats))
+		}
+		if o.path != stdout {
+			continue
+		}
+		if toStdout != nil {
+			return nil, fmt.Errorf("%s and %s results can't both be written to stdout", toStdout.format, o.format)
+		}
+		toStdout = &outputs[i]
+	}
+	if toStdout == nil {
+		format := c.String("format")
+		if _, found := formats[format]; !found {
+			return nil, fmt.Errorf("unrecognised format %q (expected one of: %s)", format, formatNames(formats))
+		}
+		outputs = append(outputs, output{format: format, path: stdout})
+	}
+	return outputs, nil
+}
+
+// flagFormat returns the format written by the flag for an individual format. These flags predate --output, and are kept
+// as aliases of it.
+func flagFormat(c *cli.Context, name string) (string, error) {
+	var format string
+	switch name {
+	case "spdx":
+		switch f := c.String("spdx-format"); f {
+		case "tag-value":
+			format = "spdx"
+		case "json":
+			format = "spdx-json"
+		default:
+			return "", fmt.Errorf("unrecognised SPDX format %q (expected one of: tag-value, json)", f)
+		}
+	case "cyclonedx":
+		switch f := c.String("cyclonedx-format"); f {
+		case "json":
+			format = "cyclonedx"
+		case "xml":
+			format = "cyclonedx-xml"
+		default:
+			return "", fmt.Errorf("unrecognised CycloneDX format %q (expected one of: json, xml)", f)
+		}
+	default:
+		return name, nil
+	}
+	if c.Bool(name + "-per-binary") {
+		format += "-per-binary"
+	}
+	return format, nil
+}
+
+// writeOutputs writes the results to each output
+func writeOutputs(outputs []output, formats map[string]outputFormat, summary scan.Summary) error {
+	for _, o := range outputs {
+		if err := formats[o.format](o.path, summary); err != nil {
+			if o.path == stdout {
+				return fmt.Errorf("failed to write %s results: %w", o.format, err)
+			}
+			return fmt.Errorf("failed to write %s results to %s: %w", o.format, o.path, err)
+		}
+	}
+	return nil
+}
+
+// textOnStdout returns true if the results written to stdout are the plain text of the template, in which case notes
+// can be written alongside them. Otherwise stdout is likely to be parsed, so notes are written to stderr instead.
+func textOnStdout(outputs []output) bool {
+	for _, o := range outputs {
+		if o.path == stdout && o.format != "text" {
+			return false
+		}
+	}
+	return true
+}
+
+func formatNames(formats map[string]outputFormat) string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// writeFile creates the file at the supplied path, and writes to it with the supplied function. If the path is "-",
+// stdout is written to instead.
+func writeFile(path string, write func(io.Writer) error) error {
+	if path == stdout {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// binaryFiles returns the path of the file written for each binary by per-binary formats, named after the binary (with
+// the supplied extension) within dir. Binaries sharing a name would overwrite each other's files, so are rejected.
+func binaryFiles(dir, ext string, binaries []model.BuildInfo) ([]string, error) {
+	if dir == stdout {
+		return nil, errors.New("a document per binary is written to a directory, which can't be stdout")
+	}
+	paths := make([]string, 0, len(binaries))
+	binaryOf := make(map[string]string, len(binaries))
+	for _, bin := range binaries {
+		path := filepath.Join(dir, filepath.Base(bin.Path)+ext)
+		if other, found := binaryOf[path]; found {
+			return nil, fmt.Errorf("binaries %s and %s would both be written to %s", other, bin.Path, path)
+		}
+		binaryOf[path] = bin.Path
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
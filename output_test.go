@@ -0,0 +1,222 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"github.com/uw-labs/lichen/internal/model"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// newContext returns the context of a scan run with the supplied arguments
+func newContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("lichen", flag.ContinueOnError)
+	for _, f := range flags {
+		require.NoError(t, f.Apply(set))
+	}
+	require.NoError(t, set.Parse(args))
+	return cli.NewContext(&cli.App{}, set, nil)
+}
+
+func TestParseOutputs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []output
+		err      string
+	}{
+		{
+			name:     "default",
+			expected: []output{{format: "text", path: stdout}},
+		},
+		{
+			name:     "format on stdout",
+			args:     []string{"--format=csv"},
+			expected: []output{{format: "csv", path: stdout}},
+		},
+		{
+			name: "outputs to files",
+			args: []string{"-o", "json=results.json", "--output", "sarif=lichen.sarif"},
+			expected: []output{
+				{format: "json", path: "results.json"},
+				{format: "sarif", path: "lichen.sarif"},
+				{format: "text", path: stdout},
+			},
+		},
+		{
+			name: "output to stdout",
+			args: []string{"-o", "json=results.json", "-o", "markdown=-"},
+			expected: []output{
+				{format: "json", path: "results.json"},
+				{format: "markdown", path: stdout},
+			},
+		},
+		{
+			name: "flags for individual formats",
+			args: []string{"--json=results.json", "--sarif=lichen.sarif", "--junit=lichen.xml", "--html=report.html"},
+			expected: []output{
+				{format: "json", path: "results.json"},
+				{format: "sarif", path: "lichen.sarif"},
+				{format: "junit", path: "lichen.xml"},
+				{format: "html", path: "report.html"},
+				{format: "text", path: stdout},
+			},
+		},
+		{
+			name: "flags for SBOM formats",
+			args: []string{"--spdx=sbom.spdx", "--cyclonedx=sbom.cdx.json"},
+			expected: []output{
+				{format: "spdx", path: "sbom.spdx"},
+				{format: "cyclonedx", path: "sbom.cdx.json"},
+				{format: "text", path: stdout},
+			},
+		},
+		{
+			name: "flags for SBOM formats, with options",
+			args: []string{"--spdx=spdx", "--spdx-format=json", "--spdx-per-binary", "--cyclonedx=sbom.cdx.xml", "--cyclonedx-format=xml"},
+			expected: []output{
+				{format: "spdx-json-per-binary", path: "spdx"},
+				{format: "cyclonedx-xml", path: "sbom.cdx.xml"},
+				{format: "text", path: stdout},
+			},
+		},
+		{
+			name: "unknown format",
+			args: []string{"-o", "yaml=results.yaml"},
+			err:  `unrecognised output format "yaml" (expected one of: `,
+		},
+		{
+			name: "unknown format on stdout",
+			args: []string{"--format=yaml"},
+			err:  `unrecognised format "yaml" (expected one of: `,
+		},
+		{
+			name: "missing path",
+			args: []string{"-o", "json="},
+			err:  `invalid output "json=" (expected format=path)`,
+		},
+		{
+			name: "missing format",
+			args: []string{"-o", "results.json"},
+			err:  `invalid output "results.json" (expected format=path)`,
+		},
+		{
+			name: "unknown SPDX format",
+			args: []string{"--spdx=sbom.spdx", "--spdx-format=rdf"},
+			err:  `unrecognised SPDX format "rdf" (expected one of: tag-value, json)`,
+		},
+		{
+			name: "two outputs to stdout",
+			args: []string{"-o", "json=-", "-o", "csv=-"},
+			err:  "json and csv results can't both be written to stdout",
+		},
+		{
+			name: "output and flag to stdout",
+			args: []string{"-o", "html=-", "--json=-"},
+			err:  "html and json results can't both be written to stdout",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := newContext(t, tc.args...)
+			formats, err := outputFormats(c, template.Must(template.New("output").Parse("")))
+			require.NoError(t, err)
+
+			outputs, err := parseOutputs(c, formats)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, outputs)
+		})
+	}
+}
+
+func TestWriteOutputs(t *testing.T) {
+	summary := scan.Summary{
+		Modules: []scan.EvaluatedModule{
+			{
+				Module: model.Module{
+					ModuleReference: model.ModuleReference{Path: "github.com/abc/xyz", Version: "v1.0.0"},
+					Licenses:        []model.License{{Name: "MIT", Confidence: 1}},
+				},
+				Decision: scan.DecisionAllowed,
+				UsedBy:   []string{"/bin/foo"},
+			},
+		},
+		Binaries: []model.BuildInfo{{Path: "/bin/foo", ModuleRefs: []model.ModuleReference{{Path: "github.com/abc/xyz", Version: "v1.0.0"}}}},
+	}
+
+	testCases := []struct {
+		name    string
+		outputs []output
+		files   []string // files expected to be written, relative to the temp directory
+		err     string
+	}{
+		{
+			name:    "single file",
+			outputs: []output{{format: "json", path: "results.json"}},
+			files:   []string{"results.json"},
+		},
+		{
+			name: "several files",
+			outputs: []output{
+				{format: "csv", path: "lichen.csv"},
+				{format: "junit", path: "lichen.xml"},
+			},
+			files: []string{"lichen.csv", "lichen.xml"},
+		},
+		{
+			name:    "per binary",
+			outputs: []output{{format: "cyclonedx-per-binary", path: "sboms"}},
+			files:   []string{filepath.Join("sboms", "foo.cdx.json")},
+		},
+		{
+			name:    "missing directory",
+			outputs: []output{{format: "csv", path: filepath.Join("missing", "lichen.csv")}},
+			err:     "failed to write csv results to ",
+		},
+		{
+			name:    "per binary to stdout",
+			outputs: []output{{format: "spdx-per-binary", path: stdout}},
+			err:     "failed to write spdx-per-binary results: a document per binary is written to a directory, which can't be stdout",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			outputs := make([]output, 0, len(tc.outputs))
+			for _, o := range tc.outputs {
+				if o.path != stdout {
+					o.path = filepath.Join(dir, o.path)
+				}
+				outputs = append(outputs, o)
+			}
+			formats, err := outputFormats(newContext(t), template.Must(template.New("output").Parse("")))
+			require.NoError(t, err)
+
+			err = writeOutputs(outputs, formats, summary)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			for _, file := range tc.files {
+				b, err := os.ReadFile(filepath.Join(dir, file))
+				require.NoError(t, err)
+				assert.Contains(t, string(b), "github.com/abc/xyz")
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/report"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// writeReport writes a self-contained HTML report of the scan results
+func writeReport(path string, summary scan.Summary) error {
+	r := report.New(summary, time.Now())
+	return writeFile(path, func(w io.Writer) error { return report.WriteHTML(w, r) })
+}
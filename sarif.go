@@ -0,0 +1,18 @@
+package main
+
+import (
+	"io"
+
+	"github.com/uw-labs/lichen/internal/sarif"
+	"github.com/uw-labs/lichen/internal/scan"
+)
+
+// writeSARIF writes a SARIF log of the modules that are not allowed. If moduleRoot is supplied, results are located
+// in its go.mod.
+func writeSARIF(path, moduleRoot string, summary scan.Summary) error {
+	log, err := sarif.New(summary, moduleRoot)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, func(w io.Writer) error { return sarif.WriteJSON(w, log) })
+}
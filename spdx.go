@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/uw-labs/lichen/internal/scan"
+	"github.com/uw-labs/lichen/internal/spdx"
+)
+
+// writeSPDX writes SPDX documents for the scan results in the supplied format (tag-value or json). If perBinary is
+// set, path is a directory to which a document for each binary is written, otherwise a single document describing
+// every binary is written to path.
+func writeSPDX(path, format string, perBinary bool, summary scan.Summary) error {
+	var (
+		write func(io.Writer, spdx.Document) error
+		ext   string
+	)
+	switch format {
+	case "tag-value":
+		write, ext = spdx.WriteTagValue, ".spdx"
+	case "json":
+		write, ext = spdx.WriteJSON, ".spdx.json"
+	default:
+		return fmt.Errorf("unrecognised SPDX format %q (expected one of: tag-value, json)", format)
+	}
+
+	created := time.Now()
+	if !perBinary {
+		doc, err := spdx.Combined(summary, created)
+		if err != nil {
+			return err
+		}
+		return writeFile(path, func(w io.Writer) error { return write(w, doc) })
+	}
+
+	paths, err := binaryFiles(path, ext, summary.Binaries)
+	if err != nil {
+		return err
+	}
+	docs, err := spdx.PerBinary(summary, created)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for SPDX documents: %w", err)
+	}
+	for i, doc := range docs {
+		doc := doc
+		if err := writeFile(paths[i], func(w io.Writer) error { return write(w, doc) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}